@@ -0,0 +1,191 @@
+package tea
+
+import (
+	"image/color"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// CapabilitiesMsg reports the results of ProbeCapabilities. Fields the
+// terminal didn't respond to in time are left at their zero value.
+type CapabilitiesMsg struct {
+	// CursorRow and CursorColumn are the one-based position of the cursor.
+	CursorRow    int
+	CursorColumn int
+
+	// BackgroundColor is the background color of the terminal.
+	BackgroundColor color.Color
+
+	// TerminalName and TerminalVersion identify the terminal, see
+	// TerminalInfo.
+	TerminalName    string
+	TerminalVersion string
+
+	// KeyboardFlags are the enabled progressive enhancement flags of the
+	// Kitty keyboard protocol. It's zero for terminals that don't support it.
+	KeyboardFlags int
+}
+
+// ProbeCapabilities is a command that queries the terminal for the cursor
+// position, its background color, its name and version, and its keyboard
+// protocol support at once. The responses are delivered to Update as a single
+// CapabilitiesMsg, once the terminal responded to every query or gave up.
+// This is handy to re-probe the terminal after it may have changed, for
+// instance after reattaching a tmux session.
+//
+// Note that a cursor on the first row may be reported in a way that's
+// indistinguishable from a function key, in which case the position is
+// missing from the results.
+func ProbeCapabilities() Msg {
+	return probeCapabilitiesMsg{}
+}
+
+// probeCapabilitiesMsg is an internal message that queries the terminal for
+// its capabilities. You can send a probeCapabilitiesMsg with
+// ProbeCapabilities.
+type probeCapabilitiesMsg struct{}
+
+// capabilitiesTimeoutMsg is an internal message that signals a capability
+// probe timed out.
+type capabilitiesTimeoutMsg struct {
+	probe *capabilityProbe
+}
+
+// requestSynchronizedOutputMsg is an internal message that queries the
+// terminal for synchronized output support, see
+// WithSynchronizedOutputIfSupported.
+type requestSynchronizedOutputMsg struct{}
+
+// modeReportMsg is reported by the input reader when the terminal responds to
+// a DECRQM query for a private mode.
+type modeReportMsg struct {
+	mode  int
+	value int
+}
+
+// supported reports whether the terminal supports the mode, that is whether
+// it's set, reset or permanently set.
+func (m modeReportMsg) supported() bool {
+	return m.value >= 1 && m.value <= 3
+}
+
+// cursorPositionMsg is reported by the input reader when the terminal responds
+// to a cursor position query.
+type cursorPositionMsg struct {
+	row, col int
+}
+
+// backgroundColorMsg is reported by the input reader when the terminal
+// responds to a background color query.
+type backgroundColorMsg struct {
+	color color.Color
+}
+
+// keyboardFlagsMsg is reported by the input reader when the terminal responds
+// to a Kitty keyboard protocol query.
+type keyboardFlagsMsg int
+
+// capabilityQueries are the queries written for ProbeCapabilities.
+const capabilityQueries = ansi.RequestCursorPositionReport +
+	ansi.RequestBackgroundColor +
+	requestTerminalVersion +
+	ansi.RequestKittyKeyboard
+
+// capability is one of the capabilities queried by ProbeCapabilities.
+type capability int
+
+const (
+	capabilityCursorPosition capability = 1 << iota
+	capabilityBackgroundColor
+	capabilityTerminalVersion
+	capabilityKeyboardFlags
+
+	allCapabilities = capabilityCursorPosition | capabilityBackgroundColor |
+		capabilityTerminalVersion | capabilityKeyboardFlags
+
+	// capabilitySynchronizedOutput is queried on its own, see
+	// WithSynchronizedOutputIfSupported.
+	capabilitySynchronizedOutput = allCapabilities + 1
+)
+
+// capabilityProbe is a ProbeCapabilities request awaiting responses.
+type capabilityProbe struct {
+	msg      CapabilitiesMsg
+	received capability
+}
+
+// probeCapabilities starts a capability probe and schedules its timeout. The
+// queries themselves are written by the renderer. If a probe is already in
+// progress, its results answer this request too.
+func (p *Program) probeCapabilities() {
+	if p.capabilityProbe != nil {
+		return
+	}
+	probe := &capabilityProbe{}
+	p.capabilityProbe = probe
+
+	timeout := time.After(terminalInfoTimeout)
+	go func() {
+		select {
+		case <-p.ctx.Done():
+		case <-timeout:
+			p.Send(capabilitiesTimeoutMsg{probe: probe})
+		}
+	}()
+}
+
+// resolveCapability records a response for the capability probe in progress,
+// if any, and delivers the results once all responses came in.
+func (p *Program) resolveCapability(c capability, fn func(*CapabilitiesMsg)) {
+	probe := p.capabilityProbe
+	if probe == nil || probe.received&c != 0 {
+		return
+	}
+	fn(&probe.msg)
+	probe.received |= c
+	if probe.received == allCapabilities {
+		p.finishCapabilityProbe(probe)
+	}
+}
+
+// finishCapabilityProbe delivers the results of the given probe, if it's
+// still in progress.
+func (p *Program) finishCapabilityProbe(probe *capabilityProbe) {
+	if p.capabilityProbe != probe {
+		return
+	}
+	p.capabilityProbe = nil
+	go p.Send(probe.msg)
+}
+
+// handleCapabilityMsg handles the internal messages of capability probes.
+func (p *Program) handleCapabilityMsg(msg Msg) {
+	switch msg := msg.(type) {
+	case probeCapabilitiesMsg:
+		p.probeCapabilities()
+
+	case capabilitiesTimeoutMsg:
+		p.finishCapabilityProbe(msg.probe)
+
+	case cursorPositionMsg:
+		p.resolveCapability(capabilityCursorPosition, func(c *CapabilitiesMsg) {
+			c.CursorRow, c.CursorColumn = msg.row, msg.col
+		})
+
+	case backgroundColorMsg:
+		p.resolveCapability(capabilityBackgroundColor, func(c *CapabilitiesMsg) {
+			c.BackgroundColor = msg.color
+		})
+
+	case terminalVersionMsg:
+		p.resolveCapability(capabilityTerminalVersion, func(c *CapabilitiesMsg) {
+			c.TerminalName, c.TerminalVersion = msg.name, msg.version
+		})
+
+	case keyboardFlagsMsg:
+		p.resolveCapability(capabilityKeyboardFlags, func(c *CapabilitiesMsg) {
+			c.KeyboardFlags = int(msg)
+		})
+	}
+}
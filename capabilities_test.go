@@ -0,0 +1,262 @@
+package tea
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+type capabilitiesTestModel struct {
+	caps *CapabilitiesMsg
+}
+
+func (m *capabilitiesTestModel) Init() Cmd {
+	return ProbeCapabilities
+}
+
+func (m *capabilitiesTestModel) Update(msg Msg) (Model, Cmd) {
+	switch msg := msg.(type) {
+	case CapabilitiesMsg:
+		m.caps = &msg
+		return m, Quit
+	case KeyMsg:
+		panic("capability report leaked into the key stream: " + msg.String())
+	}
+	return m, nil
+}
+
+func (m *capabilitiesTestModel) View() string {
+	return "caps"
+}
+
+func TestDetectCapabilityReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		width   int
+		msg     Msg
+		hasMore bool
+	}{
+		{
+			name:  "cursor position",
+			input: "\x1b[12;40Rx",
+			width: 8,
+			msg:   cursorPositionMsg{row: 12, col: 40},
+		},
+		{
+			name:  "cursor position like a function key",
+			input: "\x1b[1;2R",
+			width: 6,
+			msg:   KeyMsg{Type: KeyF15},
+		},
+		{
+			name:  "keyboard flags",
+			input: "\x1b[?15u",
+			width: 6,
+			msg:   keyboardFlagsMsg(15),
+		},
+		{
+			name:  "background color",
+			input: "\x1b]11;rgb:ffff/0000/8080\x1b\\",
+			width: 25,
+			msg:   backgroundColorMsg{color: color.RGBA{R: 0xff, G: 0x00, B: 0x80, A: 0xff}},
+		},
+		{
+			name:    "incomplete background color",
+			input:   "\x1b]11;rgb:ff",
+			hasMore: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, msg := detectOneMsg([]byte(test.input), false)
+			if test.hasMore {
+				if w != 0 {
+					t.Fatalf("expected a short read, got width %d and %#v", w, msg)
+				}
+				return
+			}
+			if w != test.width {
+				t.Errorf("expected width %d, got %d", test.width, w)
+			}
+			if !reflect.DeepEqual(msg, test.msg) {
+				t.Errorf("expected %#v, got %#v", test.msg, msg)
+			}
+		})
+	}
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	original := terminalInfoTimeout
+	terminalInfoTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { terminalInfoTimeout = original })
+
+	inR, inW := io.Pipe()
+	defer inW.Close() //nolint:errcheck
+
+	// Only respond to the terminal version and background color queries.
+	out := &queryResponder{
+		query:    capabilityQueries,
+		response: "\x1bP>|WezTerm 20240203-110809\x1b\\\x1b]11;rgb:0000/0000/0000\a",
+		input:    inW,
+	}
+
+	m := &capabilitiesTestModel{}
+	p := NewProgram(m, WithInput(inR), WithOutput(out))
+	go func() {
+		time.Sleep(3 * time.Second)
+		p.Kill()
+	}()
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &CapabilitiesMsg{
+		BackgroundColor: color.RGBA{A: 0xff},
+		TerminalName:    "WezTerm",
+		TerminalVersion: "20240203-110809",
+	}
+	if !reflect.DeepEqual(m.caps, want) {
+		t.Fatalf("expected %#v, got %#v", want, m.caps)
+	}
+}
+
+type responseRouterTestModel struct {
+	keys []string
+	caps *CapabilitiesMsg
+	msgs []Msg
+}
+
+func (m *responseRouterTestModel) Init() Cmd {
+	return ProbeCapabilities
+}
+
+func (m *responseRouterTestModel) Update(msg Msg) (Model, Cmd) {
+	m.msgs = append(m.msgs, msg)
+	switch msg := msg.(type) {
+	case KeyMsg:
+		m.keys = append(m.keys, msg.String())
+	case CapabilitiesMsg:
+		m.caps = &msg
+	}
+	if len(m.keys) > 0 && m.caps != nil {
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *responseRouterTestModel) View() string {
+	return "responses"
+}
+
+func TestUnmatchedResponses(t *testing.T) {
+	original := terminalInfoTimeout
+	terminalInfoTimeout = 200 * time.Millisecond
+	t.Cleanup(func() { terminalInfoTimeout = original })
+
+	inR, inW := io.Pipe()
+	defer inW.Close() //nolint:errcheck
+
+	// Answer the cursor position query, followed by a report nobody asked
+	// for and a key press.
+	out := &queryResponder{
+		query:    capabilityQueries,
+		response: "\x1b[12;40R\x1b[3;4Ra",
+		input:    inW,
+	}
+
+	var unmatched []string
+	m := &responseRouterTestModel{}
+	p := NewProgram(m, WithInput(inR), WithOutput(out), WithUnmatchedResponseHook(func(seq string) {
+		unmatched = append(unmatched, seq)
+	}))
+	go func() {
+		time.Sleep(3 * time.Second)
+		p.Kill()
+	}()
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(m.keys, []string{"a"}) {
+		t.Fatalf("expected only the key press to reach the model, got %q", m.keys)
+	}
+	for _, msg := range m.msgs {
+		if _, ok := msg.(terminalResponse); ok {
+			t.Fatalf("expected responses not to reach the model, got %#v", msg)
+		}
+	}
+	if m.caps.CursorRow != 12 || m.caps.CursorColumn != 40 {
+		t.Fatalf("expected the cursor position 12;40, got %d;%d", m.caps.CursorRow, m.caps.CursorColumn)
+	}
+	if !reflect.DeepEqual(unmatched, []string{"\x1b[3;4R"}) {
+		t.Fatalf("expected the unsolicited report to be unmatched, got %q", unmatched)
+	}
+}
+
+type syncOutputTestModel struct {
+	frame int
+}
+
+func (m *syncOutputTestModel) Init() Cmd { return nil }
+
+func (m *syncOutputTestModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(KeyMsg); ok {
+		m.frame++
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *syncOutputTestModel) View() string {
+	return fmt.Sprintf("frame %d", m.frame)
+}
+
+func TestSynchronizedOutputIfSupported(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   int
+		wrapped bool
+	}{
+		{"supported", 2, true},
+		{"permanently set", 3, true},
+		{"not recognized", 0, false},
+		{"permanently reset", 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inR, inW := io.Pipe()
+			defer inW.Close() //nolint:errcheck
+
+			// Answer the query, followed by a key press changing the view.
+			out := &queryResponder{
+				query:    "\x1b[?2026$p",
+				response: fmt.Sprintf("\x1b[?2026;%d$ya", tt.value),
+				input:    inW,
+			}
+			p := NewProgram(&syncOutputTestModel{}, WithInput(inR), WithOutput(out), WithSynchronizedOutputIfSupported())
+			if _, err := p.Run(); err != nil {
+				t.Fatal(err)
+			}
+
+			output := out.buf.String()
+			query := strings.Index(output, "\x1b[?2026$p")
+			if query < 0 {
+				t.Fatalf("expected the terminal to be queried, got %q", output)
+			}
+			if strings.Contains(output[:query], ansi.SetSynchronizedOutputMode) {
+				t.Fatalf("expected no synchronized output before the response, got %q", output)
+			}
+			frame := ansi.SetSynchronizedOutputMode + "\rframe 1"
+			if got := strings.Contains(output, frame); got != tt.wrapped {
+				t.Fatalf("expected the frame to be wrapped: %t, got %q", tt.wrapped, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package tea
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// CaptureOutput produces a command that captures everything written to
+// os.Stdout and os.Stderr, instead of letting it corrupt the view. This is
+// useful when calling into noisy libraries. StopCaptureOutput stops
+// capturing, passing the captured lines to fn and delivering the resulting
+// message to Update, so the model can render them:
+//
+//	func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+//	    switch msg := msg.(type) {
+//	    case startMsg:
+//	        return m, tea.Sequence(
+//	            tea.CaptureOutput(func(lines []string) tea.Msg {
+//	                return logMsg(lines)
+//	            }),
+//	            runNoisyLibrary,
+//	            tea.StopCaptureOutput,
+//	        )
+//	    case logMsg:
+//	        m.log = append(m.log, msg...)
+//	    }
+//	    // ...
+//	}
+//
+// The capture works by replacing os.Stdout and os.Stderr with a pipe, so it
+// only catches writes made through those variables while it's in progress.
+// Output written by code that kept a reference to the original files, or
+// that writes to the file descriptors directly, like C libraries, isn't
+// captured. The output of the program itself is unaffected, even if it renders
+// to os.Stdout.
+//
+// As os.Stdout and os.Stderr are shared by the whole process, only one
+// capture can be in progress at a time; starting another one has no effect.
+func CaptureOutput(fn func(lines []string) Msg) Cmd {
+	return func() Msg {
+		startOutputCapture(fn)
+		return nil
+	}
+}
+
+// StopCaptureOutput is a command that stops capturing output started with
+// CaptureOutput, restoring os.Stdout and os.Stderr, and delivers the captured
+// lines.
+func StopCaptureOutput() Msg {
+	return stopOutputCapture()
+}
+
+var (
+	captureMtx sync.Mutex
+	capture    *outputCapture
+)
+
+// outputCapture is an output capture in progress.
+type outputCapture struct {
+	fn             func(lines []string) Msg
+	stdout, stderr *os.File
+	w              *os.File
+	buf            bytes.Buffer
+	done           chan struct{}
+}
+
+// startOutputCapture starts capturing output, unless a capture is in progress.
+func startOutputCapture(fn func(lines []string) Msg) {
+	captureMtx.Lock()
+	defer captureMtx.Unlock()
+	if capture != nil {
+		return
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return
+	}
+	c := &outputCapture{
+		fn:     fn,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		w:      w,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(c.done)
+		_, _ = io.Copy(&c.buf, r)
+		_ = r.Close()
+	}()
+
+	os.Stdout, os.Stderr = w, w
+	capture = c
+}
+
+// stopOutputCapture stops the capture in progress, if any, and returns the
+// message produced for the captured lines.
+func stopOutputCapture() Msg {
+	captureMtx.Lock()
+	c := capture
+	capture = nil
+	if c != nil {
+		os.Stdout, os.Stderr = c.stdout, c.stderr
+	}
+	captureMtx.Unlock()
+
+	if c == nil {
+		return nil
+	}
+	_ = c.w.Close()
+	<-c.done
+
+	if c.fn == nil {
+		return nil
+	}
+	var lines []string
+	if out := strings.TrimSuffix(c.buf.String(), "\n"); out != "" {
+		lines = strings.Split(out, "\n")
+	}
+	return c.fn(lines)
+}
@@ -0,0 +1,57 @@
+package tea
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type capturedOutputMsg []string
+
+type captureTestModel struct {
+	captured capturedOutputMsg
+}
+
+func (m *captureTestModel) Init() Cmd {
+	return Sequence(
+		CaptureOutput(func(lines []string) Msg {
+			return capturedOutputMsg(lines)
+		}),
+		func() Msg {
+			fmt.Println("noisy library")
+			fmt.Fprintln(os.Stderr, "noisy error")
+			return nil
+		},
+		StopCaptureOutput,
+	)
+}
+
+func (m *captureTestModel) Update(msg Msg) (Model, Cmd) {
+	if msg, ok := msg.(capturedOutputMsg); ok {
+		m.captured = msg
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *captureTestModel) View() string { return "" }
+
+func TestCaptureOutput(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	stdout, stderr := os.Stdout, os.Stderr
+	m := &captureTestModel{}
+	if _, err := NewProgram(m, WithInput(&in), WithOutput(&buf)).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if os.Stdout != stdout || os.Stderr != stderr {
+		t.Fatal("expected stdout and stderr to be restored")
+	}
+	if want := (capturedOutputMsg{"noisy library", "noisy error"}); !reflect.DeepEqual(m.captured, want) {
+		t.Fatalf("expected %q, got %q", want, m.captured)
+	}
+}
@@ -0,0 +1,98 @@
+package tea
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbletea/internal/sgr"
+)
+
+// noColor reports whether the environment asks for output without colors,
+// following the NO_COLOR (https://no-color.org) and CLICOLOR
+// (https://bixense.com/clicolors) conventions:
+//
+//   - NO_COLOR set to a non-empty value disables colors. It takes precedence
+//     over everything else, as the user's explicit request.
+//   - CLICOLOR_FORCE set to a non-empty value other than "0" keeps colors on,
+//     even when CLICOLOR is "0".
+//   - CLICOLOR set to "0" disables colors.
+//
+// Colors are never dropped just because the output isn't a TTY, so forcing
+// them only matters when CLICOLOR is "0".
+func noColor(environ []string) bool {
+	if getenv(environ, "NO_COLOR") != "" {
+		return true
+	}
+	if force := getenv(environ, "CLICOLOR_FORCE"); force != "" && force != "0" {
+		return false
+	}
+	return getenv(environ, "CLICOLOR") == "0"
+}
+
+// getenv returns the value of the environment variable key in environ, or an
+// empty string if it isn't set. Like os.Getenv, the last value set wins.
+func getenv(environ []string, key string) string {
+	var value string
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == key {
+			value = v
+		}
+	}
+	return value
+}
+
+// stripColors removes the color parameters from the SGR sequences in s,
+// keeping other text attributes such as bold or underline. Sequences left
+// with no parameters are dropped.
+func stripColors(s string) string {
+	if !strings.Contains(s, "\x1b[") {
+		return s
+	}
+	return sgr.Re.ReplaceAllStringFunc(s, func(seq string) string {
+		m := sgr.Re.FindStringSubmatch(seq)
+		if m[1] == "" {
+			// A plain reset.
+			return seq
+		}
+		params := strings.Split(m[1], ";")
+		kept := params[:0:0]
+		for i := 0; i < len(params); i++ {
+			p := params[i]
+			if code, _, sub := strings.Cut(p, ":"); sub {
+				// Colors with colon separated arguments, e.g. 38:2::r:g:b.
+				if code == "38" || code == "48" || code == "58" {
+					continue
+				}
+			}
+			switch {
+			case p == "38" || p == "48" || p == "58":
+				// Skip the color arguments as well.
+				if i+1 < len(params) && params[i+1] == "5" {
+					i += 2
+				} else if i+1 < len(params) && params[i+1] == "2" {
+					i += 4
+				}
+				continue
+			case isColorParam(p):
+				continue
+			}
+			kept = append(kept, p)
+		}
+		if len(kept) == 0 {
+			return ""
+		}
+		return "\x1b[" + strings.Join(kept, ";") + "m"
+	})
+}
+
+// isColorParam reports whether the SGR parameter p sets or resets a
+// foreground, background or underline color on its own.
+func isColorParam(p string) bool {
+	switch p {
+	case "39", "49", "59":
+		return true
+	}
+	if len(p) == 2 && (p[0] == '3' || p[0] == '4' || p[0] == '9') && p[1] >= '0' && p[1] <= '7' {
+		return true
+	}
+	return len(p) == 3 && p[:2] == "10" && p[2] >= '0' && p[2] <= '7'
+}
@@ -0,0 +1,91 @@
+package tea
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNoColor(t *testing.T) {
+	const (
+		view    = "\x1b[1;31mred\x1b[0m \x1b[38;2;255;0;0mtrue\x1b[39m"
+		noColor = "\x1b[1mred\x1b[0m true"
+	)
+
+	tests := []struct {
+		name    string
+		environ []string
+		want    string
+	}{
+		{
+			name: "unset",
+			want: view,
+		},
+		{
+			name:    "NO_COLOR",
+			environ: []string{"NO_COLOR=1"},
+			want:    noColor,
+		},
+		{
+			name:    "empty NO_COLOR",
+			environ: []string{"NO_COLOR="},
+			want:    view,
+		},
+		{
+			name:    "CLICOLOR_FORCE",
+			environ: []string{"CLICOLOR_FORCE=1"},
+			want:    view,
+		},
+		{
+			name:    "CLICOLOR",
+			environ: []string{"CLICOLOR=0"},
+			want:    noColor,
+		},
+		{
+			name:    "CLICOLOR_FORCE over CLICOLOR",
+			environ: []string{"CLICOLOR=0", "CLICOLOR_FORCE=1"},
+			want:    view,
+		},
+		{
+			name:    "NO_COLOR over CLICOLOR_FORCE",
+			environ: []string{"CLICOLOR_FORCE=1", "NO_COLOR=1"},
+			want:    noColor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var in, out bytes.Buffer
+			// A non-nil environment, so the test doesn't depend on the
+			// variables it runs with.
+			environ := append([]string{"TERM=xterm"}, tt.environ...)
+			p := NewProgram(viewTestModel(view), WithInput(&in), WithOutput(&out),
+				WithEnvironment(environ), WithInitialCmds(Quit))
+			if _, err := p.Run(); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); !strings.Contains(got, tt.want) {
+				t.Fatalf("expected the frame to contain %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStripColors(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"\x1b[31mred\x1b[0m", "red\x1b[0m"},
+		{"\x1b[1;4;92;104mbright\x1b[m", "\x1b[1;4mbright\x1b[m"},
+		{"\x1b[38;5;196;1mindexed", "\x1b[1mindexed"},
+		{"\x1b[48;2;0;0;0;3mrgb", "\x1b[3mrgb"},
+		{"\x1b[58:2::1:2:3;9mcolon", "\x1b[9mcolon"},
+		{"\x1b[39;49;59mdefaults", "defaults"},
+		{"\x1b[2Kerase", "\x1b[2Kerase"},
+	}
+	for _, tt := range tests {
+		if got := stripColors(tt.in); got != tt.want {
+			t.Errorf("stripColors(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
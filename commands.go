@@ -1,7 +1,12 @@
 package tea
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
+	"unicode"
 )
 
 // Batch performs a bunch of commands concurrently with no ordering guarantees
@@ -20,6 +25,60 @@ func Batch(cmds ...Cmd) Cmd {
 // no ordering guarantees. You can send a BatchMsg with Batch.
 type BatchMsg []Cmd
 
+// BatchRace runs the given commands concurrently and delivers only the first
+// non-nil message one of them produces, discarding the others. It's useful
+// for redundant requests, such as fetching from several mirrors and taking the
+// first response:
+//
+//	func (m model) Init() tea.Cmd {
+//	    return tea.BatchRace(fetchFrom(primary), fetchFrom(mirror))
+//	}
+//
+// Once a message arrives, the losing commands created with [CmdWithContext]
+// have their context canceled, so they can stop their work. Go offers no way
+// to interrupt a function, so other commands run until they return, but their
+// messages are dropped and they never block.
+//
+// If every command returns nil, so does BatchRace.
+func BatchRace(cmds ...Cmd) Cmd {
+	var validCmds []Cmd //nolint:prealloc
+	for _, c := range cmds {
+		if c == nil {
+			continue
+		}
+		validCmds = append(validCmds, c)
+	}
+	switch len(validCmds) {
+	case 0:
+		return nil
+	case 1:
+		return validCmds[0]
+	}
+
+	return CmdWithContext(func(ctx context.Context) Msg {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// Buffered so the losing commands don't block once we've returned.
+		results := make(chan Msg, len(validCmds))
+		for _, c := range validCmds {
+			go func() {
+				msg := c()
+				if fn, ok := msg.(contextCmdMsg); ok {
+					msg = fn(ctx)
+				}
+				results <- msg
+			}()
+		}
+		for range validCmds {
+			if msg := <-results; msg != nil {
+				return msg
+			}
+		}
+		return nil
+	})
+}
+
 // Sequence runs the given commands one at a time, in order. Contrast this with
 // Batch, which runs commands concurrently.
 func Sequence(cmds ...Cmd) Cmd {
@@ -29,6 +88,25 @@ func Sequence(cmds ...Cmd) Cmd {
 // sequenceMsg is used internally to run the given commands in order.
 type sequenceMsg []Cmd
 
+// InitAll calls Init on each of the given models and batches the resulting
+// commands. This is handy when composing a model out of several sub-models.
+//
+// Example:
+//
+//	func (m model) Init() Cmd {
+//	    return tea.InitAll(m.list, m.input, m.spinner)
+//	}
+func InitAll(models ...Model) Cmd {
+	cmds := make([]Cmd, 0, len(models))
+	for _, m := range models {
+		if m == nil {
+			continue
+		}
+		cmds = append(cmds, m.Init())
+	}
+	return Batch(cmds...)
+}
+
 // compactCmds ignores any nil commands in cmds, and returns the most direct
 // command possible. That is, considering the non-nil commands, if there are
 // none it returns nil, if there is exactly one it returns that command
@@ -208,6 +286,104 @@ func SetWindowTitle(title string) Cmd {
 	}
 }
 
+// setCursorPositionMsg is an internal message used to place the cursor.
+type setCursorPositionMsg struct {
+	row int
+	col int
+}
+
+// SetCursorPosition produces a command that places the terminal cursor at
+// the given row and column, counted from zero, after every frame and shows
+// it, so text inputs can use the terminal's own cursor at the caret. The
+// position is relative to the top-left corner of the program's view, which
+// is the top-left corner of the screen in the alternate screen buffer. Rows
+// beyond the last line of the view are clamped to it.
+//
+// A negative row or column leaves the cursor where frames end again, without
+// hiding it.
+//
+// For example:
+//
+//	func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+//	    // ...
+//	    return m, tea.SetCursorPosition(m.inputRow, m.caret)
+//	}
+func SetCursorPosition(row, col int) Cmd {
+	return func() Msg {
+		return setCursorPositionMsg{row: row, col: col}
+	}
+}
+
+// setWorkingDirectoryMsg is an internal message used to report the working
+// directory to the terminal.
+type setWorkingDirectoryMsg struct {
+	host string
+	path string
+}
+
+// SetWorkingDirectory produces a command that reports the given directory to
+// the terminal as the current working directory using OSC 7. Terminals that
+// support it use this, for example, to open new tabs in the same directory.
+// This is useful for file managers and other shell-integrated programs.
+//
+// Relative paths are resolved against the process' working directory.
+//
+// For example:
+//
+//	func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+//	    // ...
+//	    return m, tea.SetWorkingDirectory(m.currentDir)
+//	}
+func SetWorkingDirectory(path string) Cmd {
+	return func() Msg {
+		// Drop control characters, which could otherwise terminate the
+		// sequence early.
+		dir := strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) {
+				return -1
+			}
+			return r
+		}, path)
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+
+		host, err := os.Hostname()
+		if err != nil {
+			host = "localhost"
+		}
+
+		return setWorkingDirectoryMsg{
+			host: host,
+			path: filepath.ToSlash(dir),
+		}
+	}
+}
+
+// rawWriteMsg is an internal message used to write a sequence to the
+// terminal. You can send a rawWriteMsg with RawWrite.
+type rawWriteMsg string
+
+// RawWrite produces a command that writes the given sequence to the terminal
+// as-is, outside of the regular rendering. It's an escape hatch for emitting
+// escape sequences Bubble Tea doesn't support (yet). It's ignored when the
+// renderer is disabled.
+//
+// Because the renderer keeps track of the lines it has drawn, sequences
+// containing newlines are rejected: RawWrite returns nil for them. Moving the
+// cursor or otherwise altering the screen may also interfere with rendering,
+// so use with care.
+//
+//	cmd := tea.RawWrite("\x1b]1337;SetUserVar=foo=YmFy\x07")
+func RawWrite(seq string) Cmd {
+	if seq == "" || strings.ContainsRune(seq, '\n') {
+		return nil
+	}
+	return func() Msg {
+		return rawWriteMsg(seq)
+	}
+}
+
 type windowSizeMsg struct{}
 
 // WindowSize is a command that queries the terminal for its current size. It
@@ -220,3 +396,41 @@ func WindowSize() Cmd {
 		return windowSizeMsg{}
 	}
 }
+
+// ErrorMsg carries an error returned by a command, see Error.
+type ErrorMsg struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrorMsg) Error() string {
+	if e.Err == nil {
+		return "<nil>"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e ErrorMsg) Unwrap() error {
+	return e.Err
+}
+
+// Error produces a command that delivers err to Update as an ErrorMsg, so that
+// errors are surfaced the same way and models handle them in one place. It
+// returns nil if err is nil.
+//
+//	case saveMsg:
+//	    if err := m.save(); err != nil {
+//	        return m, tea.Error(err)
+//	    }
+//
+// Commands doing the work themselves can return an ErrorMsg directly. See also
+// WithErrorHandler.
+func Error(err error) Cmd {
+	if err == nil {
+		return nil
+	}
+	return func() Msg {
+		return ErrorMsg{Err: err}
+	}
+}
@@ -0,0 +1,110 @@
+package tea
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// lastCmdToken is the token of the most recently started cancelable command.
+var lastCmdToken atomic.Int64
+
+// startCmdMsg is an internal message that signals the program to run a
+// cancelable command. You can send a startCmdMsg with StartCmd.
+type startCmdMsg struct {
+	token int
+	cmd   Cmd
+}
+
+// cancelCmdMsg is an internal message that signals the program to cancel a
+// command started with StartCmd. You can send a cancelCmdMsg with CancelCmd.
+type cancelCmdMsg int
+
+// cmdResultMsg is an internal message carrying the result of a cancelable
+// command. The program only delivers the result if the command hasn't been
+// canceled in the meantime.
+type cmdResultMsg struct {
+	token int
+	msg   Msg
+}
+
+// StartCmd makes the given command cancelable. It returns a token identifying
+// the command, and the command to return from Init or Update to run it. Pass
+// the token to [CancelCmd] to cancel the command:
+//
+//	func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+//	    switch msg := msg.(type) {
+//	    case openMsg:
+//	        var cmd tea.Cmd
+//	        m.fetch, cmd = tea.StartCmd(fetchDetails(msg.id))
+//	        return m, cmd
+//	    case closeMsg:
+//	        return m, tea.CancelCmd(m.fetch)
+//	    }
+//	    // ...
+//	}
+//
+// Once canceled, the message produced by the command is never delivered,
+// which saves you from handling stale messages.
+func StartCmd(cmd Cmd) (int, Cmd) {
+	token := int(lastCmdToken.Add(1))
+	return token, func() Msg {
+		return startCmdMsg{token: token, cmd: cmd}
+	}
+}
+
+// CancelCmd produces a command that cancels a command started with
+// [StartCmd]. It's a no-op if the command has already completed.
+//
+// Note that Go offers no way to interrupt a function, so the command itself
+// runs until it returns, but its message is discarded.
+func CancelCmd(token int) Cmd {
+	return func() Msg {
+		return cancelCmdMsg(token)
+	}
+}
+
+// startCmd runs a cancelable command. It must be called from the event loop.
+func (p *Program) startCmd(msg startCmdMsg) {
+	if msg.cmd == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	if p.cancelableCmds == nil {
+		p.cancelableCmds = make(map[int]context.CancelFunc)
+	}
+	p.cancelableCmds[msg.token] = cancel
+
+	go func() {
+		result := make(chan Msg, 1)
+		go func() {
+			if !p.startupOptions.has(withoutCatchPanics) {
+				defer func() {
+					if r := recover(); r != nil {
+						p.recoverFromGoPanic(r)
+					}
+				}()
+			}
+			result <- p.callCmd(msg.cmd) // this can be long.
+		}()
+
+		// Stop waiting for the command once it's canceled.
+		select {
+		case <-ctx.Done():
+		case m := <-result:
+			p.Send(cmdResultMsg{token: msg.token, msg: m})
+		}
+	}()
+}
+
+// forgetCmd cancels a command started with StartCmd and forgets about it. It
+// reports whether the command was still running. It must be called from the
+// event loop.
+func (p *Program) forgetCmd(token int) bool {
+	cancel, ok := p.cancelableCmds[token]
+	if ok {
+		cancel()
+		delete(p.cancelableCmds, token)
+	}
+	return ok
+}
@@ -0,0 +1,58 @@
+package tea
+
+// streamMsg is an internal message that signals the program to run a
+// streaming command. You can send a streamMsg with StreamCmd.
+type streamMsg func(emit func(Msg)) error
+
+// StreamErrorMsg is sent when the function of a streaming command returns an
+// error.
+type StreamErrorMsg struct {
+	Err error
+}
+
+// StreamCmd produces a command that delivers any number of messages over time,
+// which is handy for streaming sources such as the output of a subprocess. fn
+// runs in its own goroutine and calls emit for every message it wants to
+// deliver to Update, in order. If it returns an error, a StreamErrorMsg is
+// delivered.
+//
+//	cmd := tea.StreamCmd(func(emit func(tea.Msg)) error {
+//	    scanner := bufio.NewScanner(stdout)
+//	    for scanner.Scan() {
+//	        emit(lineMsg(scanner.Text()))
+//	    }
+//	    return scanner.Err()
+//	})
+//
+// emit blocks until the message is received by the program. Once the program
+// has exited, it returns right away and discards the message, so fn should
+// return as soon as it's done with its source.
+func StreamCmd(fn func(emit func(Msg)) error) Cmd {
+	if fn == nil {
+		return nil
+	}
+	return func() Msg {
+		return streamMsg(fn)
+	}
+}
+
+// runStream runs the function of a streaming command, delivering the messages
+// it emits to the program.
+func (p *Program) runStream(fn streamMsg) {
+	if !p.startupOptions.has(withoutCatchPanics) {
+		defer func() {
+			if r := recover(); r != nil {
+				p.recoverFromGoPanic(r)
+			}
+		}()
+	}
+
+	emit := func(msg Msg) {
+		if msg != nil {
+			p.Send(msg)
+		}
+	}
+	if err := fn(emit); err != nil {
+		p.Send(StreamErrorMsg{Err: err})
+	}
+}
@@ -1,7 +1,12 @@
 package tea
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -85,6 +90,72 @@ func TestBatch(t *testing.T) {
 	testMultipleCommands[BatchMsg](t, Batch)
 }
 
+func TestBatchRace(t *testing.T) {
+	t.Run("nil cmds", func(t *testing.T) {
+		if cmd := BatchRace(nil, nil); cmd != nil {
+			t.Fatalf("expected nil cmd: got %v", cmd)
+		}
+	})
+	t.Run("first message wins", func(t *testing.T) {
+		var in bytes.Buffer
+		m := &cancelTestModel{}
+		p := NewProgram(m, WithInput(&in), WithoutRenderer())
+		errc := make(chan error, 1)
+		go func() {
+			_, err := p.Run()
+			errc <- err
+		}()
+
+		var sideEffect atomic.Bool
+		canceled := make(chan struct{})
+		p.Send(BatchRace(
+			func() Msg {
+				time.Sleep(50 * time.Millisecond)
+				return "slow"
+			},
+			CmdWithContext(func(ctx context.Context) Msg {
+				select {
+				case <-ctx.Done():
+					close(canceled)
+					return nil
+				case <-time.After(50 * time.Millisecond):
+					sideEffect.Store(true)
+					return "slower"
+				}
+			}),
+			func() Msg { return nil },
+			func() Msg { return "fast" },
+		)())
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("expected the losing command to be canceled")
+		}
+		time.Sleep(150 * time.Millisecond)
+		p.Quit()
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(m.got, []string{"fast"}) {
+			t.Fatalf("expected only the fastest message, got %v", m.got)
+		}
+		if sideEffect.Load() {
+			t.Fatal("expected the side effect of the canceled command to be suppressed")
+		}
+	})
+	t.Run("all nil", func(t *testing.T) {
+		cmd := BatchRace(func() Msg { return nil }, func() Msg { return nil })
+		fn, ok := cmd().(contextCmdMsg)
+		if !ok {
+			t.Fatalf("expected a context command, got %T", cmd())
+		}
+		if msg := fn(context.Background()); msg != nil {
+			t.Fatalf("expected nil msg: got %v", msg)
+		}
+	})
+}
+
 func TestSequence(t *testing.T) {
 	testMultipleCommands[sequenceMsg](t, Sequence)
 }
@@ -113,3 +184,254 @@ func testMultipleCommands[T ~[]Cmd](t *testing.T, createFn func(cmd ...Cmd) Cmd)
 		}
 	})
 }
+
+type initTestModel struct {
+	testModel
+	init Cmd
+}
+
+func (m *initTestModel) Init() Cmd {
+	return m.init
+}
+
+func TestInitAll(t *testing.T) {
+	increment := func() Msg { return incrementMsg{} }
+
+	t.Run("no cmds", func(t *testing.T) {
+		if cmd := InitAll(&testModel{}, nil, &initTestModel{}); cmd != nil {
+			t.Fatalf("expected nil, got %+v", cmd)
+		}
+	})
+
+	t.Run("batched cmds", func(t *testing.T) {
+		cmd := InitAll(
+			&initTestModel{init: increment},
+			&testModel{},
+			&initTestModel{init: increment},
+			&initTestModel{init: increment},
+		)
+		batch, ok := cmd().(BatchMsg)
+		if !ok {
+			t.Fatalf("expected a BatchMsg, got %T", cmd())
+		}
+		if len(batch) != 3 {
+			t.Fatalf("expected 3 cmds, got %d", len(batch))
+		}
+
+		m := &testModel{}
+		for _, c := range batch {
+			m.Update(c())
+		}
+		if got := m.counter.Load(); got != 3 {
+			t.Fatalf("expected all cmds to run, got counter %v", got)
+		}
+	})
+}
+
+type cancelTestModel struct {
+	got []string
+}
+
+func (m *cancelTestModel) Init() Cmd { return nil }
+
+func (m *cancelTestModel) Update(msg Msg) (Model, Cmd) {
+	if s, ok := msg.(string); ok {
+		m.got = append(m.got, s)
+	}
+	return m, nil
+}
+
+func (m *cancelTestModel) View() string { return "" }
+
+func TestCancelCmd(t *testing.T) {
+	delayed := func(s string) Cmd {
+		return func() Msg {
+			time.Sleep(50 * time.Millisecond)
+			return s
+		}
+	}
+
+	var in bytes.Buffer
+	m := &cancelTestModel{}
+	p := NewProgram(m, WithInput(&in), WithoutRenderer())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	canceled, cmd := StartCmd(delayed("canceled"))
+	p.SendSync(cmd())
+	_, cmd = StartCmd(delayed("completed"))
+	p.SendSync(cmd())
+	p.SendSync(CancelCmd(canceled)())
+
+	time.Sleep(150 * time.Millisecond)
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(m.got, []string{"completed"}) {
+		t.Fatalf("expected only the completed command's message, got %v", m.got)
+	}
+	if len(p.cancelableCmds) != 0 {
+		t.Fatalf("expected finished commands to be forgotten, got %d", len(p.cancelableCmds))
+	}
+}
+
+type commandTimeoutTestModel struct {
+	msgs []Msg
+}
+
+func (m *commandTimeoutTestModel) Init() Cmd { return nil }
+
+func (m *commandTimeoutTestModel) Update(msg Msg) (Model, Cmd) {
+	switch msg.(type) {
+	case string, CommandTimeoutMsg:
+		m.msgs = append(m.msgs, msg)
+	}
+	return m, nil
+}
+
+func (m *commandTimeoutTestModel) View() string { return "" }
+
+func TestCommandTimeout(t *testing.T) {
+	var in bytes.Buffer
+	m := &commandTimeoutTestModel{}
+	p := NewProgram(m, WithInput(&in), WithoutRenderer(), WithCommandTimeout(20*time.Millisecond))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	canceled := make(chan struct{})
+	p.Send(BatchMsg{
+		CmdWithContext(func(ctx context.Context) Msg {
+			select {
+			case <-ctx.Done():
+				close(canceled)
+			case <-time.After(time.Second):
+			}
+			return "hung"
+		}),
+		CmdWithContext(func(context.Context) Msg { return "quick" }),
+		// Timers block on purpose, so they're never timed out.
+		Tick(60*time.Millisecond, func(time.Time) Msg { return "tick" }),
+	})
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the context of the hung command to be canceled")
+	}
+	time.Sleep(150 * time.Millisecond)
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Msg{"quick", CommandTimeoutMsg{Timeout: 20 * time.Millisecond}, "tick"}
+	if !reflect.DeepEqual(m.msgs, want) {
+		t.Fatalf("expected %v, got %v", want, m.msgs)
+	}
+}
+
+type errorTestModel struct {
+	errs []error
+}
+
+func (m *errorTestModel) Init() Cmd { return nil }
+
+func (m *errorTestModel) Update(msg Msg) (Model, Cmd) {
+	if msg, ok := msg.(ErrorMsg); ok {
+		m.errs = append(m.errs, msg.Err)
+	}
+	return m, nil
+}
+
+func (m *errorTestModel) View() string { return "" }
+
+func TestError(t *testing.T) {
+	if cmd := Error(nil); cmd != nil {
+		t.Fatalf("expected nil cmd for a nil error: got %v", cmd)
+	}
+	if got := (ErrorMsg{}).Error(); got != "<nil>" {
+		t.Fatalf("expected an ErrorMsg without an error to describe itself, got %q", got)
+	}
+
+	errSave := errors.New("could not save")
+	var handled []error
+
+	var in bytes.Buffer
+	m := &errorTestModel{}
+	p := NewProgram(m, WithInput(&in), WithoutRenderer(), WithErrorHandler(func(err error) Cmd {
+		handled = append(handled, err)
+		return Quit
+	}))
+	go func() {
+		p.Send(ErrorMsg{})
+		p.Send(Error(errSave)())
+	}()
+
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.errs, []error{nil, errSave}) {
+		t.Fatalf("expected the model to receive nil and %v, got %v", errSave, m.errs)
+	}
+	if !reflect.DeepEqual(handled, []error{errSave}) {
+		t.Fatalf("expected the handler to receive %v, got %v", errSave, handled)
+	}
+}
+
+func TestStreamCmd(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+	go p.Send(StreamCmd(func(emit func(Msg)) error {
+		for range 3 {
+			emit(incrementMsg{})
+		}
+		emit(Quit())
+		return nil
+	})())
+
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if m.counter.Load() != 3 {
+		t.Fatalf("counter should be 3, got %v", m.counter.Load())
+	}
+}
+
+func TestStreamCmdError(t *testing.T) {
+	var in bytes.Buffer
+	var streamErr error
+
+	m := &cancelTestModel{}
+	p := NewProgram(m, WithInput(&in), WithoutRenderer(), WithFilter(func(_ Model, msg Msg) Msg {
+		if msg, ok := msg.(StreamErrorMsg); ok {
+			streamErr = msg.Err
+			return QuitMsg{}
+		}
+		return msg
+	}))
+	go p.Send(StreamCmd(func(emit func(Msg)) error {
+		emit("line")
+		return errors.New("stream failed")
+	})())
+
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.got, []string{"line"}) {
+		t.Fatalf("expected the emitted message, got %v", m.got)
+	}
+	if streamErr == nil || streamErr.Error() != "stream failed" {
+		t.Fatalf("expected the stream error, got %v", streamErr)
+	}
+}
@@ -0,0 +1,84 @@
+package tea
+
+import (
+	"context"
+	"time"
+)
+
+// CommandTimeoutMsg is sent when a command created with [CmdWithContext]
+// didn't return a message within the timeout set with [WithCommandTimeout].
+// The command's message, if it ever returns one, is dropped.
+type CommandTimeoutMsg struct {
+	Timeout time.Duration
+}
+
+// contextCmdMsg is an internal message used to run a command that's passed a
+// context. You can send a contextCmdMsg with CmdWithContext.
+type contextCmdMsg func(ctx context.Context) Msg
+
+// CmdWithContext produces a command that calls fn with a context, which is
+// canceled when the program exits or, with [WithCommandTimeout], when the
+// command runs out of time. Commands doing I/O, such as HTTP requests, should
+// pass the context on so they stop as soon as their message is no longer
+// wanted:
+//
+//	func fetch(url string) tea.Cmd {
+//	    return tea.CmdWithContext(func(ctx context.Context) tea.Msg {
+//	        req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	        // ...
+//	    })
+//	}
+func CmdWithContext(fn func(ctx context.Context) Msg) Cmd {
+	if fn == nil {
+		return nil
+	}
+	return func() Msg {
+		return contextCmdMsg(fn)
+	}
+}
+
+// callCmd calls cmd and returns its message. Commands created with
+// CmdWithContext are run with the program's context, see runContextCmd.
+func (p *Program) callCmd(cmd Cmd) Msg {
+	msg := cmd()
+	if fn, ok := msg.(contextCmdMsg); ok {
+		return p.runContextCmd(fn)
+	}
+	return msg
+}
+
+// runContextCmd runs a command created with CmdWithContext and returns its
+// message. With WithCommandTimeout, a command that doesn't return in time has
+// its context canceled and is abandoned, and a CommandTimeoutMsg is returned
+// instead. Go offers no way to interrupt a function, so a command ignoring
+// its context keeps running on its own goroutine until it returns.
+func (p *Program) runContextCmd(fn contextCmdMsg) Msg {
+	if p.commandTimeout <= 0 {
+		return fn(p.ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(p.ctx, p.commandTimeout)
+	defer cancel()
+
+	result := make(chan Msg, 1)
+	go func() {
+		if !p.startupOptions.has(withoutCatchPanics) {
+			defer func() {
+				if r := recover(); r != nil {
+					p.recoverFromGoPanic(r)
+				}
+			}()
+		}
+		result <- fn(ctx)
+	}()
+
+	select {
+	case msg := <-result:
+		return msg
+	case <-ctx.Done():
+		if p.ctx.Err() != nil {
+			return nil
+		}
+		return CommandTimeoutMsg{Timeout: p.commandTimeout}
+	}
+}
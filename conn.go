@@ -0,0 +1,79 @@
+package tea
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/muesli/cancelreader"
+)
+
+// InputErrorMsg is sent to the program when reading input from a connection
+// set with [WithConn] fails for a reason other than the connection being
+// closed.
+type InputErrorMsg struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e InputErrorMsg) Error() string {
+	return e.Err.Error()
+}
+
+// connReader is a cancelreader.CancelReader for network connections. Reads
+// are canceled by moving the read deadline of the connection into the past.
+type connReader struct {
+	conn     net.Conn
+	canceled atomic.Bool
+}
+
+func newConnReader(conn net.Conn) *connReader {
+	// Clear any deadline left over from a previously canceled reader.
+	_ = conn.SetReadDeadline(time.Time{})
+
+	// Detect dead peers on long-lived TCP connections.
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetKeepAlive(true)
+	}
+
+	return &connReader{conn: conn}
+}
+
+// Read implements io.Reader.
+func (r *connReader) Read(b []byte) (int, error) {
+	if r.canceled.Load() {
+		return 0, cancelreader.ErrCanceled
+	}
+	n, err := r.conn.Read(b)
+	if err != nil && r.canceled.Load() {
+		return n, cancelreader.ErrCanceled
+	}
+	return n, err //nolint:wrapcheck
+}
+
+// Cancel implements cancelreader.CancelReader.
+func (r *connReader) Cancel() bool {
+	r.canceled.Store(true)
+	return r.conn.SetReadDeadline(time.Now()) == nil
+}
+
+// Close implements io.Closer. The connection is owned by the caller, so it's
+// left open.
+func (r *connReader) Close() error {
+	return nil
+}
+
+// handleConnError handles the error that ended reading from a connection. A
+// closed connection quits the program, while other errors are sent to the
+// program as an InputErrorMsg.
+func (p *Program) handleConnError(err error) {
+	switch {
+	case err == nil, errors.Is(err, cancelreader.ErrCanceled):
+	case errors.Is(err, io.EOF), errors.Is(err, net.ErrClosed), errors.Is(err, io.ErrClosedPipe):
+		p.Send(QuitMsg{})
+	default:
+		p.Send(InputErrorMsg{Err: err})
+	}
+}
@@ -0,0 +1,120 @@
+package tea
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+type connTestModel struct {
+	keys string
+	err  error
+}
+
+func (m *connTestModel) Init() Cmd { return nil }
+
+func (m *connTestModel) Update(msg Msg) (Model, Cmd) {
+	switch msg := msg.(type) {
+	case KeyMsg:
+		m.keys += msg.String()
+	case InputErrorMsg:
+		m.err = msg.Err
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *connTestModel) View() string { return "conn\n" }
+
+// failingConn is a net.Conn whose reads fail with err.
+type failingConn struct {
+	net.Conn
+	err error
+}
+
+func (c failingConn) Read([]byte) (int, error) { return 0, c.err }
+
+func runConnTestProgram(t *testing.T, conn net.Conn) (*connTestModel, chan error) {
+	t.Helper()
+	m := &connTestModel{}
+	p := NewProgram(m, WithConn(conn))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+	return m, errc
+}
+
+func waitForConnTestProgram(t *testing.T, errc chan error) {
+	t.Helper()
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("program did not quit in time")
+	}
+}
+
+func TestWithConn(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()           //nolint:errcheck
+	go io.Copy(io.Discard, client) //nolint:errcheck
+
+	m, errc := runConnTestProgram(t, server)
+
+	if _, err := client.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	client.Close() //nolint:errcheck
+
+	waitForConnTestProgram(t, errc)
+	if m.keys != "abc" {
+		t.Fatalf("expected keys %q, got %q", "abc", m.keys)
+	}
+}
+
+func TestWithInputConn(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()           //nolint:errcheck
+	go io.Copy(io.Discard, client) //nolint:errcheck
+
+	// Without WithConn, a connection is read like any other input, so
+	// closing it doesn't quit the program.
+	m := &connTestModel{}
+	p := NewProgram(m, WithInput(server), WithOutput(server))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	client.Close() //nolint:errcheck
+	select {
+	case err := <-errc:
+		t.Fatalf("expected the program to keep running, got %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	p.Quit()
+	waitForConnTestProgram(t, errc)
+}
+
+func TestWithConnInputError(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()           //nolint:errcheck
+	defer client.Close()           //nolint:errcheck
+	go io.Copy(io.Discard, client) //nolint:errcheck
+
+	errBroken := errors.New("broken")
+	m, errc := runConnTestProgram(t, failingConn{Conn: server, err: errBroken})
+
+	waitForConnTestProgram(t, errc)
+	if !errors.Is(m.err, errBroken) {
+		t.Fatalf("expected input error %v, got %v", errBroken, m.err)
+	}
+}
@@ -0,0 +1,78 @@
+package tea
+
+import "time"
+
+// QueryCursorPosition is a command that queries the terminal for the current
+// position of the cursor. The one-based row and column are passed to fn and
+// the resulting message is delivered to Update. Unlike the position reported
+// by ProbeCapabilities, it can be queried at any time, for instance to find
+// out where the program ended up after output it doesn't control.
+//
+// The terminal's response is handled by the program, so it never reaches
+// Update as key presses. If the terminal doesn't respond in a timely manner,
+// fn is called with zeros.
+//
+//	type cursorMsg struct{ row, col int }
+//
+//	func (m model) Init() tea.Cmd {
+//	    return tea.QueryCursorPosition(func(row, col int) tea.Msg {
+//	        return cursorMsg{row, col}
+//	    })
+//	}
+func QueryCursorPosition(fn func(row, col int) Msg) Cmd {
+	return func() Msg {
+		return cursorPositionRequestMsg{fn: fn}
+	}
+}
+
+// cursorPositionRequestMsg is an internal message that queries the terminal
+// for the cursor position. You can send a cursorPositionRequestMsg with
+// QueryCursorPosition.
+type cursorPositionRequestMsg struct {
+	fn func(row, col int) Msg
+}
+
+// cursorPositionTimeoutMsg is an internal message that signals a pending
+// QueryCursorPosition request timed out.
+type cursorPositionTimeoutMsg struct {
+	req *cursorPositionRequest
+}
+
+// cursorPositionRequest is a QueryCursorPosition request awaiting a response.
+type cursorPositionRequest struct {
+	fn func(row, col int) Msg
+}
+
+// requestCursorPosition registers a pending QueryCursorPosition request and
+// schedules its timeout. The query itself is written by the renderer.
+func (p *Program) requestCursorPosition(fn func(row, col int) Msg) {
+	req := &cursorPositionRequest{fn: fn}
+	p.cursorPositionRequests = append(p.cursorPositionRequests, req)
+
+	timeout := time.After(terminalInfoTimeout)
+	go func() {
+		select {
+		case <-p.ctx.Done():
+		case <-timeout:
+			p.Send(cursorPositionTimeoutMsg{req: req})
+		}
+	}()
+}
+
+// resolveCursorPosition delivers the given position to the pending
+// QueryCursorPosition requests. If req is not nil only that request is
+// resolved.
+func (p *Program) resolveCursorPosition(req *cursorPositionRequest, row, col int) {
+	pending := p.cursorPositionRequests[:0]
+	for _, r := range p.cursorPositionRequests {
+		if req != nil && r != req {
+			pending = append(pending, r)
+			continue
+		}
+		if r.fn != nil {
+			msg := r.fn(row, col)
+			go p.Send(msg)
+		}
+	}
+	p.cursorPositionRequests = pending
+}
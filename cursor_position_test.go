@@ -0,0 +1,86 @@
+package tea
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+type cursorPositionTestMsg struct {
+	row, col int
+}
+
+type cursorPositionTestModel struct {
+	pos *cursorPositionTestMsg
+}
+
+func (m *cursorPositionTestModel) Init() Cmd {
+	return QueryCursorPosition(func(row, col int) Msg {
+		return cursorPositionTestMsg{row: row, col: col}
+	})
+}
+
+func (m *cursorPositionTestModel) Update(msg Msg) (Model, Cmd) {
+	switch msg := msg.(type) {
+	case cursorPositionTestMsg:
+		m.pos = &msg
+		return m, Quit
+	case KeyMsg:
+		panic("cursor position report leaked into the key stream: " + msg.String())
+	}
+	return m, nil
+}
+
+func (m *cursorPositionTestModel) View() string {
+	return "cursor"
+}
+
+func TestQueryCursorPosition(t *testing.T) {
+	inR, inW := io.Pipe()
+	defer inW.Close() //nolint:errcheck
+
+	out := &queryResponder{
+		query:    ansi.RequestCursorPositionReport,
+		response: "\x1b[7;21R",
+		input:    inW,
+	}
+
+	m := &cursorPositionTestModel{}
+	p := NewProgram(m, WithInput(inR), WithOutput(out))
+	go func() {
+		time.Sleep(3 * time.Second)
+		p.Kill()
+	}()
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := cursorPositionTestMsg{row: 7, col: 21}
+	if m.pos == nil || *m.pos != want {
+		t.Fatalf("expected %#v, got %#v", want, m.pos)
+	}
+}
+
+func TestQueryCursorPositionTimeout(t *testing.T) {
+	original := terminalInfoTimeout
+	terminalInfoTimeout = 10 * time.Millisecond
+	t.Cleanup(func() { terminalInfoTimeout = original })
+
+	var buf bytes.Buffer
+	m := &cursorPositionTestModel{}
+	p := NewProgram(m, WithInput(nil), WithOutput(&buf))
+	go func() {
+		time.Sleep(3 * time.Second)
+		p.Kill()
+	}()
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.pos == nil || *m.pos != (cursorPositionTestMsg{}) {
+		t.Fatalf("expected a zero position on timeout, got %#v", m.pos)
+	}
+}
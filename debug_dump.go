@@ -0,0 +1,204 @@
+package tea
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// maxDebugDumpDepth is how deep DebugDump descends into nested values.
+const maxDebugDumpDepth = 32
+
+// DebugStater can be implemented by a [Model] to choose what [DebugDump]
+// shows, for instance to include unexported state or leave out large values.
+type DebugStater interface {
+	// DebugState returns the value to dump in place of the model.
+	DebugState() interface{}
+}
+
+// debugDumpMsg is an internal message that signals the program to dump the
+// model. You can send a debugDumpMsg with DebugDump.
+type debugDumpMsg struct {
+	fn func(string) Msg
+}
+
+// DebugDump is a special command that dumps the current model as indented
+// JSON and delivers it with the message returned by fn. It's meant for
+// inspecting a running program, for instance bound to a debug key:
+//
+//	case "ctrl+d":
+//	    return m, tea.DebugDump(func(s string) tea.Msg {
+//	        return dumpMsg(s)
+//	    })
+//
+// Only exported struct fields are dumped, unless the model implements
+// [DebugStater]. Channels and functions are shown as their type rather than
+// followed, and pointers already being dumped as "<cycle>".
+func DebugDump(fn func(string) Msg) Cmd {
+	return func() Msg {
+		return debugDumpMsg{fn: fn}
+	}
+}
+
+// debugDump returns the model as indented JSON. It must be called from the
+// event loop, which owns the model.
+func debugDump(model Model) string {
+	var v interface{} = model
+	if s, ok := model.(DebugStater); ok {
+		v = s.DebugState()
+	}
+
+	var buf bytes.Buffer
+	writeDebugValue(&buf, reflect.ValueOf(v), map[uintptr]bool{}, 0)
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return buf.String()
+	}
+	return out.String()
+}
+
+// writeDebugValue writes v to buf as JSON. seen holds the pointers being
+// dumped, to stop at cycles.
+func writeDebugValue(buf *bytes.Buffer, v reflect.Value, seen map[uintptr]bool, depth int) {
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return
+	}
+	if depth > maxDebugDumpDepth {
+		writeDebugString(buf, "<too deep>")
+		return
+	}
+
+	if v.CanInterface() {
+		if m, ok := v.Interface().(json.Marshaler); ok && (v.Kind() != reflect.Ptr || !v.IsNil()) {
+			if b, err := json.Marshal(m); err == nil {
+				buf.Write(b)
+				return
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return
+		}
+		writeDebugString(buf, "<"+v.Type().String()+">")
+
+	case reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return
+		}
+		writeDebugValue(buf, v.Elem(), seen, depth+1)
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return
+		}
+		if seen[v.Pointer()] {
+			writeDebugString(buf, "<cycle>")
+			return
+		}
+		seen[v.Pointer()] = true
+		writeDebugValue(buf, v.Elem(), seen, depth+1)
+		delete(seen, v.Pointer())
+
+	case reflect.Struct:
+		buf.WriteByte('{')
+		n := 0
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if n > 0 {
+				buf.WriteByte(',')
+			}
+			n++
+			writeDebugString(buf, f.Name)
+			buf.WriteByte(':')
+			writeDebugValue(buf, v.Field(i), seen, depth+1)
+		}
+		buf.WriteByte('}')
+
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return
+		}
+		type entry struct {
+			name string
+			key  reflect.Value
+		}
+		entries := make([]entry, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			entries = append(entries, entry{name: fmt.Sprint(k), key: k})
+		}
+		slices.SortFunc(entries, func(a, b entry) int {
+			return strings.Compare(a.name, b.name)
+		})
+		buf.WriteByte('{')
+		for i, e := range entries {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeDebugString(buf, e.name)
+			buf.WriteByte(':')
+			writeDebugValue(buf, v.MapIndex(e.key), seen, depth+1)
+		}
+		buf.WriteByte('}')
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buf.WriteString("null")
+			return
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeDebugValue(buf, v.Index(i), seen, depth+1)
+		}
+		buf.WriteByte(']')
+
+	case reflect.Bool:
+		buf.WriteString(fmt.Sprint(v.Bool()))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(fmt.Sprint(v.Int()))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buf.WriteString(fmt.Sprint(v.Uint()))
+
+	case reflect.Float32, reflect.Float64:
+		b, err := json.Marshal(v.Float())
+		if err != nil {
+			// NaN and infinities have no JSON representation.
+			writeDebugString(buf, fmt.Sprint(v.Float()))
+			return
+		}
+		buf.Write(b)
+
+	case reflect.String:
+		writeDebugString(buf, v.String())
+
+	default:
+		writeDebugString(buf, fmt.Sprint(v))
+	}
+}
+
+func writeDebugString(buf *bytes.Buffer, s string) {
+	// Keep the placeholders readable.
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	buf.Truncate(buf.Len() - 1) // Drop the trailing newline.
+}
@@ -0,0 +1,89 @@
+package tea
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type debugDumpTestModel struct {
+	Name    string
+	Count   int
+	Tags    []string
+	Scores  map[string]float64
+	Updates chan Msg
+	OnSave  func()
+	Parent  *debugDumpTestModel
+	secret  string
+	dump    string
+}
+
+type dumpMsg string
+
+func (m *debugDumpTestModel) Init() Cmd {
+	return DebugDump(func(s string) Msg { return dumpMsg(s) })
+}
+
+func (m *debugDumpTestModel) Update(msg Msg) (Model, Cmd) {
+	if msg, ok := msg.(dumpMsg); ok {
+		m.dump = string(msg)
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *debugDumpTestModel) View() string { return "" }
+
+func TestDebugDump(t *testing.T) {
+	m := &debugDumpTestModel{
+		Name:    "gopher",
+		Count:   3,
+		Tags:    []string{"a", "b"},
+		Scores:  map[string]float64{"z": 1.5, "a": 2},
+		Updates: make(chan Msg),
+		secret:  "hidden",
+	}
+	m.Parent = m
+
+	var in bytes.Buffer
+	p := NewProgram(m, WithInput(&in), WithoutRenderer())
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !json.Valid([]byte(m.dump)) {
+		t.Fatalf("expected valid JSON, got %s", m.dump)
+	}
+	for _, want := range []string{
+		`"Name": "gopher"`,
+		`"Count": 3`,
+		`"a": 2`,
+		`"z": 1.5`,
+		`"Updates": "<chan tea.Msg>"`,
+		`"OnSave": null`,
+		`"Parent": "<cycle>"`,
+	} {
+		if !strings.Contains(m.dump, want) {
+			t.Errorf("expected %s in dump:\n%s", want, m.dump)
+		}
+	}
+	if strings.Contains(m.dump, "hidden") {
+		t.Errorf("expected unexported fields to be left out:\n%s", m.dump)
+	}
+}
+
+type debugStaterTestModel struct {
+	debugDumpTestModel
+}
+
+func (m *debugStaterTestModel) DebugState() interface{} {
+	return map[string]string{"secret": m.secret}
+}
+
+func TestDebugDumpDebugStater(t *testing.T) {
+	got := debugDump(&debugStaterTestModel{debugDumpTestModel{secret: "shown"}})
+	if want := "{\n  \"secret\": \"shown\"\n}"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,108 @@
+package tea
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// debugOverlay collects the statistics shown by WithDebugOverlay and
+// composites them onto rendered frames. It's only accessed from the event
+// loop.
+type debugOverlay struct {
+	// width of the window, if known.
+	width int
+
+	msgs    uint64
+	lastMsg string
+
+	// Rates are sampled at most once per second.
+	sampledAt     time.Time
+	sampledMsgs   uint64
+	sampledFrames uint64
+	msgRate       float64
+	frameRate     float64
+}
+
+// observe records a message processed by the event loop.
+func (d *debugOverlay) observe(msg Msg) {
+	d.msgs++
+	d.lastMsg = fmt.Sprintf("%T", msg)
+	if size, ok := msg.(WindowSizeMsg); ok {
+		d.width = size.Width
+	}
+}
+
+// sample updates the message and frame rates if at least a second has passed
+// since they were last sampled.
+func (d *debugOverlay) sample(frames uint64, now time.Time) {
+	if d.sampledAt.IsZero() {
+		d.sampledAt, d.sampledMsgs, d.sampledFrames = now, d.msgs, frames
+		return
+	}
+	elapsed := now.Sub(d.sampledAt).Seconds()
+	if elapsed < 1 {
+		return
+	}
+	d.msgRate = float64(d.msgs-d.sampledMsgs) / elapsed
+	d.frameRate = float64(frames-d.sampledFrames) / elapsed
+	d.sampledAt, d.sampledMsgs, d.sampledFrames = now, d.msgs, frames
+}
+
+// String returns the diagnostic line.
+func (d *debugOverlay) String() string {
+	last := d.lastMsg
+	if last == "" {
+		last = "-"
+	}
+	return fmt.Sprintf("msgs/s:%.0f fps:%.0f last:%s", d.msgRate, d.frameRate, last)
+}
+
+// compose overwrites the bottom-right corner of the view, rendered at the
+// given width, with the diagnostic line. If the width isn't known the line is
+// added below the view instead.
+func (d *debugOverlay) compose(view string, width int) string {
+	overlay := d.String()
+	if width <= 0 {
+		return view + "\n" + overlay
+	}
+
+	if ansi.StringWidth(overlay) > width {
+		overlay = ansi.Truncate(overlay, width, "")
+	}
+	room := width - ansi.StringWidth(overlay)
+
+	lines := strings.Split(view, "\n")
+	last := ansi.Truncate(lines[len(lines)-1], room, "")
+	if w := ansi.StringWidth(last); w < room {
+		last += strings.Repeat(" ", room-w)
+	}
+	// Reset any styling left open by the model so it doesn't bleed into the
+	// overlay.
+	lines[len(lines)-1] = last + ansi.ResetStyle + overlay
+	return strings.Join(lines, "\n")
+}
+
+// renderedFrames returns the number of frames the renderer has written to
+// the output, if it keeps track of it.
+func (p *Program) renderedFrames() uint64 {
+	if r, ok := p.renderer.(*standardRenderer); ok {
+		return atomic.LoadUint64(&r.framesRendered)
+	}
+	return 0
+}
+
+// frameWidth returns the width frames are rendered at, which is narrower than
+// the window with WithMaxWidth. It falls back to the width of the window for
+// renderers that don't keep track of it.
+func (p *Program) frameWidth() int {
+	if r, ok := p.renderer.(*standardRenderer); ok {
+		r.mtx.Lock()
+		defer r.mtx.Unlock()
+		return r.renderWidth()
+	}
+	return p.debugOverlay.width
+}
@@ -0,0 +1,90 @@
+package tea
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDebugOverlayCompose(t *testing.T) {
+	d := &debugOverlay{}
+	d.observe(WindowSizeMsg{Width: 60, Height: 10})
+
+	now := time.Now()
+	d.sample(0, now)
+	d.observe(KeyMsg{Type: KeyEnter})
+	d.observe(KeyMsg{Type: KeyEnter})
+	d.sample(120, now.Add(2*time.Second))
+
+	got := d.compose("first line\nsecond line", d.width)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("overlay should not add lines when the width is known, got %q", got)
+	}
+	if lines[0] != "first line" {
+		t.Errorf("overlay should leave the rest of the frame alone, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "second line") {
+		t.Errorf("overlay should not overwrite the model's output, got %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[1], "msgs/s:1 fps:60 last:tea.KeyMsg") {
+		t.Errorf("expected stats in the bottom-right corner, got %q", lines[1])
+	}
+	if w := ansi.StringWidth(lines[1]); w != 60 {
+		t.Errorf("expected the last line to span the window width, got %d", w)
+	}
+
+	t.Run("narrow window", func(t *testing.T) {
+		d.observe(WindowSizeMsg{Width: 10, Height: 10})
+		got := d.compose("success", d.width)
+		if w := ansi.StringWidth(got); w != 10 {
+			t.Fatalf("overlay should respect the window width, got %q", got)
+		}
+	})
+}
+
+func TestDebugOverlayMaxWidth(t *testing.T) {
+	r, _ := newStdRendererForTest(t)
+	r.maxWidth = 40
+	r.handleMessages(WindowSizeMsg{Width: 80, Height: 10})
+
+	p := NewProgram(nil, WithDebugOverlay())
+	p.renderer = r
+	p.debugOverlay = &debugOverlay{}
+	p.debugOverlay.observe(WindowSizeMsg{Width: 80, Height: 10})
+
+	if w := p.frameWidth(); w != 40 {
+		t.Fatalf("expected the frame width to be capped to 40, got %d", w)
+	}
+	got := p.debugOverlay.compose("success", p.frameWidth())
+	if w := ansi.StringWidth(got); w != 40 {
+		t.Fatalf("expected the overlay to fit the capped width, got %q", got)
+	}
+	if !strings.Contains(got, "last:tea.WindowSizeMsg") {
+		t.Fatalf("expected the overlay to be visible, got %q", got)
+	}
+}
+
+func TestDebugOverlay(t *testing.T) {
+	var in bytes.Buffer
+	in.WriteString("q")
+
+	r := &frameRecorder{}
+	p := NewProgram(&testModel{}, WithInput(&in), WithDebugOverlay())
+	p.renderer = r
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	frames := r.recorded()
+	if len(frames) == 0 {
+		t.Fatal("no frames rendered")
+	}
+	last := frames[len(frames)-1]
+	if !strings.Contains(last, "success") || !strings.Contains(last, "msgs/s:") {
+		t.Fatalf("expected the frame to contain the model's view and stats, got %q", last)
+	}
+}
@@ -0,0 +1,29 @@
+package tea
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// isDisconnect reports whether err, returned by a write to the output, means
+// the terminal went away.
+func isDisconnect(err error) bool {
+	return errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.EIO) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// handleOutputError stops the program with ErrTerminalDisconnected when
+// writing to the output fails because the terminal went away. Other errors are
+// ignored, as a frame that failed to render is repainted with the next one.
+func (p *Program) handleOutputError(err error) {
+	if !isDisconnect(err) {
+		return
+	}
+	select {
+	case p.errs <- fmt.Errorf("%w: %w", ErrTerminalDisconnected, err):
+	default:
+	}
+	p.cancel()
+}
@@ -101,3 +101,45 @@ func TestTeaExec(t *testing.T) {
 		})
 	}
 }
+
+type execOutputBufferTestModel struct {
+	p        *Program
+	buffered bool
+}
+
+func (m *execOutputBufferTestModel) Init() Cmd {
+	return ExecProcess(exec.Command("true"), func(err error) Msg {
+		return execFinishedMsg{err}
+	})
+}
+
+func (m *execOutputBufferTestModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(execFinishedMsg); ok {
+		r := m.p.renderer.(*standardRenderer)
+		r.mtx.Lock()
+		m.buffered = !r.stopped
+		r.mtx.Unlock()
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *execOutputBufferTestModel) View() string {
+	return "buffered"
+}
+
+func TestTeaExecKeepsOutputBuffer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("true is not available on windows")
+	}
+
+	var in, out bytes.Buffer
+	m := &execOutputBufferTestModel{}
+	m.p = NewProgram(m, WithInput(&in), WithOutput(&out), WithOutputBuffer(4096))
+	if _, err := m.p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !m.buffered {
+		t.Fatal("expected the output to be buffered again once the terminal is restored")
+	}
+}
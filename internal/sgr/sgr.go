@@ -0,0 +1,39 @@
+// Package sgr inspects SGR (Select Graphic Rendition) escape sequences, the
+// sequences setting text attributes such as colors or bold.
+package sgr
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Reset resets all text attributes.
+const Reset = "\x1b[0m"
+
+// Re matches an SGR sequence. The first submatch holds its parameters.
+var Re = regexp.MustCompile(`\x1b\[([0-9;:]*)m`)
+
+// Open reports whether s leaves any text attributes set, that is, whether it
+// contains SGR sequences that aren't followed by a reset.
+func Open(s string) bool {
+	open := false
+	for _, m := range Re.FindAllStringSubmatch(s, -1) {
+		params := strings.Split(m[1], ";")
+		for i := 0; i < len(params); i++ {
+			switch params[i] {
+			case "", "0":
+				open = false
+				continue
+			case "38", "48", "58":
+				// Skip the color arguments, which may contain zeros.
+				if i+1 < len(params) && params[i+1] == "5" {
+					i += 2
+				} else if i+1 < len(params) && params[i+1] == "2" {
+					i += 4
+				}
+			}
+			open = true
+		}
+	}
+	return open
+}
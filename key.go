@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -91,6 +94,132 @@ func (k Key) String() (str string) {
 	return ""
 }
 
+// MarshalText implements [encoding.TextMarshaler]. Unlike [Key.String], the
+// result is unambiguous and can be parsed back with [Key.UnmarshalText], which
+// makes it suitable for logging key events and replaying them later.
+//
+// Named keys are encoded by name, runes are prefixed with "runes:" and pasted
+// text with "paste:". The alt modifier is encoded as an "alt+" prefix:
+//
+//	alt+ctrl+a
+//	runes:hello
+//	paste:"multi\nline"
+//
+// Text that is empty, starts with a double quote or contains non-printable
+// characters is quoted using Go syntax.
+//
+// The format is only understood by [Key.UnmarshalText]; there's no other
+// parser for key sequences.
+func (k Key) MarshalText() ([]byte, error) {
+	var buf strings.Builder
+	if k.Alt {
+		buf.WriteString("alt+")
+	}
+	switch {
+	case k.Type == KeyRunes && k.Paste:
+		buf.WriteString(keyReprPaste)
+		buf.WriteString(quoteKeyRunes(k.Runes))
+	case k.Type == KeyRunes:
+		buf.WriteString(keyReprRunes)
+		buf.WriteString(quoteKeyRunes(k.Runes))
+	case k.Type == KeySpace:
+		buf.WriteString(keyReprSpace)
+	default:
+		name, ok := keyNames[k.Type]
+		if !ok {
+			return nil, fmt.Errorf("bubbletea: cannot marshal unknown key type %d", int(k.Type))
+		}
+		buf.WriteString(name)
+	}
+	return []byte(buf.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler]. It parses the
+// representation produced by [Key.MarshalText].
+func (k *Key) UnmarshalText(text []byte) error {
+	s := string(text)
+	var key Key
+
+	// "alt+" is only a modifier if something follows it; a literal named key
+	// never starts with it.
+	if rest, ok := strings.CutPrefix(s, "alt+"); ok && rest != "" {
+		key.Alt = true
+		s = rest
+	}
+
+	switch {
+	case strings.HasPrefix(s, keyReprRunes), strings.HasPrefix(s, keyReprPaste):
+		key.Type = KeyRunes
+		key.Paste = strings.HasPrefix(s, keyReprPaste)
+		runes, err := unquoteKeyRunes(s[len(keyReprRunes):])
+		if err != nil {
+			return err
+		}
+		key.Runes = runes
+	case s == keyReprSpace:
+		key.Type = KeySpace
+		key.Runes = spaceRunes
+	default:
+		t, ok := keyNameTypes[s]
+		if !ok {
+			return fmt.Errorf("bubbletea: unknown key %q", string(text))
+		}
+		key.Type = t
+	}
+
+	*k = key
+	return nil
+}
+
+// MarshalText implements [encoding.TextMarshaler]. See [Key.MarshalText].
+func (k KeyMsg) MarshalText() ([]byte, error) {
+	return Key(k).MarshalText()
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler]. See
+// [Key.UnmarshalText].
+func (k *KeyMsg) UnmarshalText(text []byte) error {
+	return (*Key)(k).UnmarshalText(text)
+}
+
+// Prefixes used by the text representation of keys. Both prefixes have the
+// same length, which UnmarshalText relies on.
+const (
+	keyReprRunes = "runes:"
+	keyReprPaste = "paste:"
+	keyReprSpace = "space"
+)
+
+// quoteKeyRunes returns the text representation of runes, quoting it when it
+// would otherwise be ambiguous or unprintable.
+func quoteKeyRunes(runes []rune) string {
+	s := string(runes)
+	if s == "" || s[0] == '"' {
+		return strconv.Quote(s)
+	}
+	for _, r := range runes {
+		if !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// unquoteKeyRunes is the inverse of quoteKeyRunes.
+func unquoteKeyRunes(s string) ([]rune, error) {
+	if strings.HasPrefix(s, `"`) {
+		u, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("bubbletea: invalid quoted key runes %s: %w", s, err)
+		}
+		s = u
+	}
+	if s == "" {
+		return nil, nil
+	}
+	return []rune(s), nil
+}
+
 // KeyType indicates the key pressed, such as KeyEnter or KeyBreak or KeyCtrlC.
 // All other keys will be type KeyRunes. To get the rune value, check the Rune
 // method on a Key struct, or use the Key.String() method:
@@ -350,6 +479,15 @@ var keyNames = map[KeyType]string{
 	KeyF20:            "f20",
 }
 
+// keyNameTypes maps the friendly names in keyNames back to their key types.
+var keyNameTypes = func() map[string]KeyType {
+	m := make(map[string]KeyType, len(keyNames))
+	for t, name := range keyNames {
+		m[name] = t
+	}
+	return m
+}()
+
 // Sequence mappings.
 var sequences = map[string]Key{
 	// Arrow keys
@@ -553,22 +691,76 @@ func (u unknownCSISequenceMsg) String() string {
 
 var spaceRunes = []rune{' '}
 
+// inputInterceptor recognizes custom sequences in raw input, see
+// WithInputInterceptor.
+type inputInterceptor func(raw []byte) (consumed int, msgs []Msg)
+
 // readAnsiInputs reads keypress and mouse inputs from a TTY and produces messages
-// containing information about the key or mouse events accordingly.
-func readAnsiInputs(ctx context.Context, msgs chan<- Msg, input io.Reader) error {
+// containing information about the key or mouse events accordingly. If
+// intercept is set, it gets a chance to handle the input before it's parsed.
+//
+// If escTimeout is positive, input ending in what may be the beginning of an
+// escape sequence is held for up to escTimeout while waiting for the rest of
+// the sequence, so a lone escape key press can be told apart from a sequence
+// split across reads.
+func readAnsiInputs(ctx context.Context, msgs chan<- Msg, input io.Reader, intercept inputInterceptor, escTimeout time.Duration) error {
 	var buf [256]byte
 
+	send := func(msg Msg) error {
+		select {
+		case msgs <- msg:
+			return nil
+		case <-ctx.Done():
+			err := ctx.Err()
+			if err != nil {
+				err = fmt.Errorf("found context error while reading input: %w", err)
+			}
+			return err
+		}
+	}
+
+	// Waiting for the rest of an escape sequence requires reading in the
+	// background, so the wait can time out.
+	var chunks <-chan inputChunk
+	if escTimeout > 0 {
+		done := make(chan struct{})
+		defer close(done)
+		chunks = readChunks(input, len(buf), done)
+	}
+
 	var leftOverFromPrevIteration []byte
+	waitForEscape := false
 loop:
 	for {
-		// Read and block.
-		numBytes, err := input.Read(buf[:])
-		if err != nil {
-			return fmt.Errorf("error reading input: %w", err)
-		}
-		b := buf[:numBytes]
-		if leftOverFromPrevIteration != nil {
-			b = append(leftOverFromPrevIteration, b...)
+		var b []byte
+		var timedOut bool
+		if chunks == nil {
+			// Read and block.
+			numBytes, err := input.Read(buf[:])
+			if err != nil {
+				return fmt.Errorf("error reading input: %w", err)
+			}
+			b = buf[:numBytes]
+		} else {
+			var timer *time.Timer
+			var timeout <-chan time.Time
+			if waitForEscape {
+				timer = time.NewTimer(escTimeout)
+				timeout = timer.C
+			}
+			var c inputChunk
+			select {
+			case c = <-chunks:
+			case <-timeout:
+				timedOut = true
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			if c.err != nil {
+				return fmt.Errorf("error reading input: %w", c.err)
+			}
+			b = c.b
 		}
 
 		// If we had a short read (numBytes < len(buf)), we're sure that
@@ -578,10 +770,37 @@ loop:
 		// be more data in the OS buffer ready to be read in, to complete
 		// the last message in the input. In that case, we will retry with
 		// the left over data in the next iteration.
-		canHaveMoreData := numBytes == len(buf)
+		canHaveMoreData := len(b) == len(buf)
+
+		if leftOverFromPrevIteration != nil {
+			b = append(leftOverFromPrevIteration, b...)
+		}
+		waitForEscape = false
 
 		var i, w int
 		for i, w = 0, 0; i < len(b); i += w {
+			if intercept != nil {
+				consumed, imsgs := intercept(b[i:])
+				for _, msg := range imsgs {
+					if err := send(msg); err != nil {
+						return err
+					}
+				}
+				if consumed > 0 {
+					w = min(consumed, len(b)-i)
+					continue
+				}
+			}
+
+			if chunks != nil && !canHaveMoreData && !timedOut && incompleteEscape(b[i:]) {
+				// This may be a lone escape key press or the beginning of
+				// an escape sequence. Wait a little for the rest of it.
+				leftOverFromPrevIteration = make([]byte, 0, len(b[i:])+len(buf))
+				leftOverFromPrevIteration = append(leftOverFromPrevIteration, b[i:]...)
+				waitForEscape = true
+				continue loop
+			}
+
 			var msg Msg
 			w, msg = detectOneMsg(b[i:], canHaveMoreData)
 			if w == 0 {
@@ -592,13 +811,7 @@ loop:
 				continue loop
 			}
 
-			select {
-			case msgs <- msg:
-			case <-ctx.Done():
-				err := ctx.Err()
-				if err != nil {
-					err = fmt.Errorf("found context error while reading input: %w", err)
-				}
+			if err := send(msg); err != nil {
 				return err
 			}
 		}
@@ -606,6 +819,61 @@ loop:
 	}
 }
 
+// inputChunk is the result of a single read of the input.
+type inputChunk struct {
+	b   []byte
+	err error
+}
+
+// readChunks reads from input in the background until reading fails or done
+// is closed.
+func readChunks(input io.Reader, size int, done <-chan struct{}) <-chan inputChunk {
+	chunks := make(chan inputChunk)
+	go func() {
+		for {
+			b := make([]byte, size)
+			n, err := input.Read(b)
+			select {
+			case chunks <- inputChunk{b: b[:n], err: err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return chunks
+}
+
+// incompleteEscape reports whether b is an escape character, possibly
+// followed by the beginning of a CSI or SS3 sequence, which more input could
+// complete.
+func incompleteEscape(b []byte) bool {
+	if len(b) == 0 || b[0] != '\x1b' {
+		return false
+	}
+	if len(b) == 1 {
+		return true
+	}
+	switch b[1] {
+	case '\x1b':
+		// An escape sequence with the Alt modifier.
+		return incompleteEscape(b[1:])
+	case 'O':
+		return len(b) == 2 //nolint:mnd
+	case '[':
+		for _, c := range b[2:] {
+			// Parameter and intermediate bytes lead up to the final byte.
+			if c < 0x20 || c > 0x3f {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 var (
 	unknownCSIRe  = regexp.MustCompile(`^\x1b\[[\x30-\x3f]*[\x20-\x2f]*[\x40-\x7e]`)
 	mouseSGRRegex = regexp.MustCompile(`(\d+);(\d+);(\d+)([Mm])`)
@@ -635,6 +903,20 @@ func detectOneMsg(b []byte, canHaveMoreData bool) (w int, msg Msg) {
 		return w, msg
 	}
 
+	// Detect terminal version reports.
+	var foundTV bool
+	foundTV, w, msg = detectTerminalVersion(b, canHaveMoreData)
+	if foundTV {
+		return w, msg
+	}
+
+	// Detect responses to capability queries.
+	var foundCR bool
+	foundCR, w, msg = detectCapabilityReport(b, canHaveMoreData)
+	if foundCR {
+		return w, msg
+	}
+
 	// Detect bracketed paste.
 	var foundbp bool
 	foundbp, w, msg = detectBracketedPaste(b)
@@ -2,8 +2,12 @@ package tea
 
 import (
 	"bytes"
+	"regexp"
 	"sort"
+	"strconv"
 	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
 )
 
 // extSequences is used by the map-based algorithm below. It contains
@@ -127,3 +131,98 @@ func detectReportFocus(input []byte) (hasRF bool, width int, msg Msg) {
 	}
 	return false, 0, nil
 }
+
+// detectTerminalVersion detects a terminal's response to an XTVERSION query,
+// which is a DCS sequence of the form "ESC P > | text ST".
+func detectTerminalVersion(input []byte, canHaveMoreData bool) (hasTV bool, width int, msg Msg) {
+	const tvStart = "\x1bP>|"
+	if len(input) < len(tvStart) {
+		// The start of a report may have been split across reads. Ask for
+		// more data so it doesn't leak into the key stream. Without more data
+		// the input is an actual key press, such as alt+P.
+		if canHaveMoreData && len(input) > 1 && bytes.HasPrefix([]byte(tvStart), input) {
+			return true, 0, nil
+		}
+		return false, 0, nil
+	}
+	if string(input[:len(tvStart)]) != tvStart {
+		return false, 0, nil
+	}
+
+	// The report is terminated by ST, though some terminals use BEL.
+	body := input[len(tvStart):]
+	end, termLen := bytes.Index(body, []byte("\x1b\\")), 2
+	if bel := bytes.IndexByte(body, '\a'); bel != -1 && (end == -1 || bel < end) {
+		end, termLen = bel, 1
+	}
+	if end == -1 {
+		// We haven't seen the end of the report yet. Tell the outer loop we
+		// have done a short read and we want more.
+		return true, 0, nil
+	}
+
+	name, version := parseTerminalVersion(string(body[:end]))
+	return true, len(tvStart) + end + termLen, terminalVersionMsg{name: name, version: version}
+}
+
+var (
+	cursorPositionRe = regexp.MustCompile(`^\x1b\[(\d+);(\d+)R`)
+	keyboardFlagsRe  = regexp.MustCompile(`^\x1b\[\?(\d+)u`)
+	modeReportRe     = regexp.MustCompile(`^\x1b\[\?(\d+);(\d+)\$y`)
+)
+
+// detectCapabilityReport detects a terminal's response to one of the queries
+// of ProbeCapabilities: a cursor position report, a Kitty keyboard protocol
+// report, or a background color report.
+func detectCapabilityReport(input []byte, canHaveMoreData bool) (hasCR bool, width int, msg Msg) {
+	if m := cursorPositionRe.FindSubmatch(input); m != nil {
+		// Some cursor positions look exactly like function keys with
+		// modifiers. Keys take precedence.
+		if _, ok := extSequences[string(m[0])]; ok {
+			return false, 0, nil
+		}
+		row, _ := strconv.Atoi(string(m[1]))
+		col, _ := strconv.Atoi(string(m[2]))
+		return true, len(m[0]), cursorPositionMsg{row: row, col: col}
+	}
+
+	if m := keyboardFlagsRe.FindSubmatch(input); m != nil {
+		flags, _ := strconv.Atoi(string(m[1]))
+		return true, len(m[0]), keyboardFlagsMsg(flags)
+	}
+
+	if m := modeReportRe.FindSubmatch(input); m != nil {
+		mode, _ := strconv.Atoi(string(m[1]))
+		value, _ := strconv.Atoi(string(m[2]))
+		return true, len(m[0]), modeReportMsg{mode: mode, value: value}
+	}
+
+	// The background color is reported as "OSC 11 ; color ST", though some
+	// terminals use BEL.
+	const bgStart = "\x1b]11;"
+	if len(input) < len(bgStart) {
+		// Ask for more data if the start of a report may have been split
+		// across reads, like detectTerminalVersion does.
+		if canHaveMoreData && len(input) > 1 && bytes.HasPrefix([]byte(bgStart), input) {
+			return true, 0, nil
+		}
+		return false, 0, nil
+	}
+	if string(input[:len(bgStart)]) != bgStart {
+		return false, 0, nil
+	}
+
+	body := input[len(bgStart):]
+	end, termLen := bytes.Index(body, []byte("\x1b\\")), 2
+	if bel := bytes.IndexByte(body, '\a'); bel != -1 && (end == -1 || bel < end) {
+		end, termLen = bel, 1
+	}
+	if end == -1 {
+		// We haven't seen the end of the report yet. Tell the outer loop we
+		// have done a short read and we want more.
+		return true, 0, nil
+	}
+
+	c := ansi.XParseColor(string(body[:end]))
+	return true, len(bgStart) + end + termLen, backgroundColorMsg{color: c}
+}
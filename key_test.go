@@ -7,6 +7,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"log"
 	"math/rand"
 	"reflect"
 	"runtime"
@@ -493,6 +494,14 @@ func TestReadInput(t *testing.T) {
 				KeyMsg{Type: KeyRunes, Runes: []rune("a\x03\nb"), Paste: true},
 			},
 		},
+		{
+			"[a\x1b[Ab\x1b[<0;1;1M] o",
+			[]byte("\x1b[200~a\x1b[Ab\x1b[<0;1;1M\x1b[201~o"),
+			[]Msg{
+				KeyMsg{Type: KeyRunes, Runes: []rune("a\x1b[Ab\x1b[<0;1;1M"), Paste: true},
+				KeyMsg{Type: KeyRunes, Runes: []rune("o")},
+			},
+		},
 	}
 	if runtime.GOOS != "windows" {
 		// Sadly, utf8.DecodeRune([]byte(0xfe)) returns a valid rune on windows.
@@ -547,6 +556,68 @@ func TestReadInput(t *testing.T) {
 	}
 }
 
+func TestReadInputPasteAcrossReads(t *testing.T) {
+	// Every reader is read separately, splitting the escape sequence in the
+	// paste across reads.
+	msgs := testReadInputs(t, io.MultiReader(
+		strings.NewReader("\x1b[200~echo \x1b"),
+		strings.NewReader("[A"),
+		strings.NewReader("\x1b[201~"),
+	))
+
+	want := []Msg{KeyMsg{Type: KeyRunes, Runes: []rune("echo \x1b[A"), Paste: true}}
+	if !reflect.DeepEqual(msgs, want) {
+		t.Fatalf("expected:\n%#v\ngot:\n%#v", want, msgs)
+	}
+}
+
+func TestReadInputEscapeTimeout(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+
+	r, w := io.Pipe()
+	defer r.Close() //nolint:errcheck
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msgsC := make(chan Msg)
+	go func() {
+		_ = readAnsiInputs(ctx, msgsC, r, nil, timeout)
+	}()
+	next := func() Msg {
+		t.Helper()
+		select {
+		case msg := <-msgsC:
+			return msg
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for input event")
+			return nil
+		}
+	}
+
+	// A lone escape is reported once the timeout has elapsed.
+	start := time.Now()
+	if _, err := w.Write([]byte("\x1b")); err != nil {
+		t.Fatal(err)
+	}
+	if msg := next(); !reflect.DeepEqual(msg, KeyMsg{Type: KeyEscape}) {
+		t.Fatalf("expected the escape key, got %#v", msg)
+	}
+	if elapsed := time.Since(start); elapsed < timeout {
+		t.Fatalf("expected the escape key after %v, got it after %v", timeout, elapsed)
+	}
+
+	// A sequence split across reads is reported as a single key.
+	if _, err := w.Write([]byte("\x1b")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("[A")); err != nil {
+		t.Fatal(err)
+	}
+	if msg := next(); !reflect.DeepEqual(msg, KeyMsg{Type: KeyUp}) {
+		t.Fatalf("expected the up key, got %#v", msg)
+	}
+}
+
 func testReadInputs(t *testing.T, input io.Reader) []Msg {
 	// We'll check that the input reader finishes at the end
 	// without error.
@@ -568,7 +639,7 @@ func testReadInputs(t *testing.T, input io.Reader) []Msg {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		inputErr = readAnsiInputs(ctx, msgsC, input)
+		inputErr = readAnsiInputs(ctx, msgsC, input, nil, 0)
 		msgsC <- nil
 	}()
 
@@ -732,3 +803,71 @@ func BenchmarkDetectSequenceMap(b *testing.B) {
 		}
 	}
 }
+
+func TestKeyMarshalTextRoundTrip(t *testing.T) {
+	keys := []KeyMsg{
+		{Type: KeyEnter},
+		{Type: KeyCtrlA, Alt: true},
+		{Type: KeyRunes, Runes: []rune("hello")},
+		{Type: KeyRunes, Runes: []rune("ctrl+c")},
+		{Type: KeyRunes, Runes: []rune(`"quoted"`), Alt: true},
+		{Type: KeyRunes, Runes: []rune("multi\nline paste"), Paste: true},
+		{Type: KeySpace, Runes: spaceRunes},
+		{Type: KeyEscape, Alt: true},
+		{Type: KeyF20},
+	}
+
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+	for _, k := range keys {
+		text, err := k.MarshalText()
+		if err != nil {
+			t.Fatalf("marshal %#v: %v", k, err)
+		}
+		logger.Println(string(text))
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(keys) {
+		t.Fatalf("expected %d logged keys, got %d: %q", len(keys), len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var got KeyMsg
+		if err := got.UnmarshalText([]byte(line)); err != nil {
+			t.Fatalf("unmarshal %q: %v", line, err)
+		}
+		if !reflect.DeepEqual(got, keys[i]) {
+			t.Errorf("round trip of %q: expected %#v, got %#v", line, keys[i], got)
+		}
+	}
+
+	t.Run("format", func(t *testing.T) {
+		for _, tc := range []struct {
+			key  KeyMsg
+			want string
+		}{
+			{KeyMsg{Type: KeyCtrlA, Alt: true}, "alt+ctrl+a"},
+			{KeyMsg{Type: KeyRunes, Runes: []rune("hello")}, "runes:hello"},
+			{KeyMsg{Type: KeyRunes, Runes: []rune("a\tb")}, `runes:"a\tb"`},
+			{KeyMsg{Type: KeyRunes, Runes: []rune("x"), Paste: true}, "paste:x"},
+		} {
+			text, err := tc.key.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(text) != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, text)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := (KeyMsg{Type: KeyType(99999)}).MarshalText(); err == nil {
+			t.Error("expected an error marshalling an unknown key type")
+		}
+		var k KeyMsg
+		if err := k.UnmarshalText([]byte("hyper+q")); err == nil {
+			t.Error("expected an error unmarshalling an unknown key")
+		}
+	})
+}
@@ -14,12 +14,12 @@ import (
 	"github.com/muesli/cancelreader"
 )
 
-func readInputs(ctx context.Context, msgs chan<- Msg, input io.Reader) error {
+func readInputs(ctx context.Context, msgs chan<- Msg, input io.Reader, intercept inputInterceptor, escTimeout time.Duration) error {
 	if coninReader, ok := input.(*conInputReader); ok {
 		return readConInputs(ctx, msgs, coninReader)
 	}
 
-	return readAnsiInputs(ctx, msgs, localereader.NewReader(input))
+	return readAnsiInputs(ctx, msgs, localereader.NewReader(input), intercept, escTimeout)
 }
 
 func readConInputs(ctx context.Context, msgsch chan<- Msg, con *conInputReader) error {
@@ -0,0 +1,77 @@
+package tea
+
+import "strings"
+
+// LogPane keeps a bounded, scrollable region of log lines at the top of the
+// view, above content rendered normally such as a status bar. The lines are
+// drawn with the scroll region commands rather than the renderer, so
+// streaming many of them is cheap, and the renderer is told to leave the
+// pane's lines alone so it doesn't overwrite them.
+//
+// Render the pane's View above the rest of the view, and sync it whenever the
+// window is resized:
+//
+//	func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+//	    switch msg := msg.(type) {
+//	    case tea.WindowSizeMsg:
+//	        return m, m.logs.Sync()
+//	    case logMsg:
+//	        return m, m.logs.Append(string(msg))
+//	    }
+//	    // ...
+//	}
+//
+//	func (m model) View() string {
+//	    return m.logs.View() + "\n" + m.status
+//	}
+//
+// As it relies on the scroll region commands, the same caveats apply: the
+// pane is positioned relative to the top of the window, so it's best suited
+// for full-window applications.
+type LogPane struct {
+	maxLines int
+	lines    []string
+}
+
+// NewLogPane returns a log pane retaining the last maxLines lines.
+func NewLogPane(maxLines int) *LogPane {
+	return &LogPane{maxLines: max(maxLines, 1)}
+}
+
+// Append adds lines to the bottom of the pane, scrolling older lines out once
+// it's full. It returns the command that draws the new lines.
+func (l *LogPane) Append(lines ...string) Cmd {
+	if len(lines) == 0 {
+		return nil
+	}
+	l.lines = append(l.lines, lines...)
+	if n := len(l.lines) - l.maxLines; n > 0 {
+		l.lines = append(l.lines[:0], l.lines[n:]...)
+	}
+	if len(lines) > l.maxLines {
+		lines = lines[len(lines)-l.maxLines:]
+	}
+	return ScrollDown(lines, 0, l.maxLines)
+}
+
+// Sync returns the command that reserves the pane's lines and redraws them.
+// Return it once the window size is known, and again on every resize.
+func (l *LogPane) Sync() Cmd {
+	return SyncScrollArea(l.Lines(), 0, l.maxLines)
+}
+
+// Clear returns the command that gives the pane's lines back to the renderer.
+func (l *LogPane) Clear() Cmd {
+	return ClearScrollArea
+}
+
+// Lines returns the lines currently retained by the pane.
+func (l *LogPane) Lines() []string {
+	return append([]string(nil), l.lines...)
+}
+
+// View returns the space taken by the pane in the view. The lines themselves
+// are drawn by the scroll region commands, so it's blank.
+func (l *LogPane) View() string {
+	return strings.Repeat("\n", l.maxLines-1)
+}
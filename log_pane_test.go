@@ -0,0 +1,61 @@
+package tea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestLogPane(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	r.handleMessages(WindowSizeMsg{Width: 80, Height: 24})
+
+	pane := NewLogPane(3)
+	render := func() {
+		r.write(pane.View() + "\nstatus")
+		r.flush()
+	}
+	render()
+	r.handleMessages(pane.Sync()())
+	render()
+
+	for i := range 5 {
+		out.Reset()
+		r.handleMessages(pane.Append("log " + string(rune('a'+i)))())
+		if got := out.String(); !strings.Contains(got, ansi.SetTopBottomMargins(0, 3)) {
+			t.Fatalf("expected the line to be scrolled into the pane, got %q", got)
+		}
+		render()
+	}
+
+	if want := []string{"log c", "log d", "log e"}; !reflect.DeepEqual(pane.Lines(), want) {
+		t.Fatalf("expected the pane to retain %q, got %q", want, pane.Lines())
+	}
+	for i := range 3 {
+		if _, ok := r.ignoreLines[i]; !ok {
+			t.Fatalf("line %d of the pane should be ignored by the renderer", i)
+		}
+	}
+	if _, ok := r.ignoreLines[3]; ok {
+		t.Fatal("the status line should not be ignored by the renderer")
+	}
+	if got := r.lastRenderedLines; len(got) != 4 || got[3] != "status" {
+		t.Fatalf("expected the status line to persist below the pane, got %q", got)
+	}
+
+	out.Reset()
+	r.handleMessages(pane.Append("x", "y", "z", "w")())
+	if !strings.Contains(out.String(), "\r\ny\r\nz\r\nw") {
+		t.Fatalf("expected only the last lines to be drawn, got %q", out.String())
+	}
+	if want := []string{"y", "z", "w"}; !reflect.DeepEqual(pane.Lines(), want) {
+		t.Fatalf("expected the pane to retain %q, got %q", want, pane.Lines())
+	}
+
+	r.handleMessages(pane.Clear()())
+	if r.ignoreLines != nil {
+		t.Fatal("clearing the pane should give its lines back to the renderer")
+	}
+}
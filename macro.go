@@ -0,0 +1,98 @@
+package tea
+
+import "sync"
+
+// keyRecorder records the keys delivered to the model, see WithKeyRecording
+// and Program.StartMacro.
+type keyRecorder struct {
+	mtx       sync.Mutex
+	keys      []KeyMsg
+	recording bool
+	macro     []KeyMsg
+}
+
+// replayedKeyMsg is an internal message used to replay a key with PlayMacro.
+type replayedKeyMsg KeyMsg
+
+// macroRecording reports whether a macro is being recorded.
+func (p *Program) macroRecording() bool {
+	r := &p.keyRecorder
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r.recording
+}
+
+// recordKey records a key the model has processed. The key is part of a
+// macro if one was being recorded before the model processed it, and still
+// is, so the keys starting and stopping a macro aren't part of it.
+func (p *Program) recordKey(key KeyMsg, wasRecording bool) {
+	r := &p.keyRecorder
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if p.startupOptions.has(withKeyRecording) {
+		r.keys = append(r.keys, key)
+	}
+	if wasRecording && r.recording {
+		r.macro = append(r.macro, key)
+	}
+}
+
+// RecordedKeys returns all the keys the model has processed so far, in
+// order, when recording keys with [WithKeyRecording].
+func (p *Program) RecordedKeys() []KeyMsg {
+	r := &p.keyRecorder
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return append([]KeyMsg(nil), r.keys...)
+}
+
+// StartMacro starts recording a macro: the keys processed by the model from
+// now on, until StopMacro is called. It can be called from Update, in which
+// case the key being processed isn't part of the macro. Starting a macro
+// discards the keys of a macro that was still being recorded.
+func (p *Program) StartMacro() {
+	r := &p.keyRecorder
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.recording = true
+	r.macro = nil
+}
+
+// StopMacro stops recording a macro and returns the keys recorded since
+// StartMacro was called, which can be replayed with [PlayMacro]. Like
+// StartMacro, it can be called from Update, in which case the key being
+// processed isn't part of the macro.
+func (p *Program) StopMacro() []KeyMsg {
+	r := &p.keyRecorder
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	keys := r.macro
+	r.recording = false
+	r.macro = nil
+	return keys
+}
+
+// PlayMacro produces a command that replays the given keys, as recorded with
+// StopMacro, one after another. The keys are processed like keys typed by
+// the user, except that they're never dropped by [WithKeyRepeatThrottle].
+//
+// For example, to replay a macro like vim's @ does:
+//
+//	case tea.KeyMsg:
+//	    if msg.String() == "@" {
+//	        return m, tea.PlayMacro(m.macro)
+//	    }
+func PlayMacro(keys []KeyMsg) Cmd {
+	cmds := make([]Cmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = func() Msg {
+			return replayedKeyMsg(key)
+		}
+	}
+	return Sequence(cmds...)
+}
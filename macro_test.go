@@ -0,0 +1,63 @@
+package tea
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+type macroTestModel struct {
+	p     *Program
+	keys  []string
+	macro []KeyMsg
+}
+
+func (m *macroTestModel) Init() Cmd { return nil }
+
+func (m *macroTestModel) Update(msg Msg) (Model, Cmd) {
+	key, ok := msg.(KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	m.keys = append(m.keys, key.String())
+	switch key.String() {
+	case "q":
+		if m.macro == nil {
+			m.p.StartMacro()
+			m.macro = []KeyMsg{}
+		} else {
+			m.macro = m.p.StopMacro()
+		}
+	case "@":
+		return m, Sequence(PlayMacro(m.macro), Quit)
+	}
+	return m, nil
+}
+
+func (m *macroTestModel) View() string { return "" }
+
+func TestMacro(t *testing.T) {
+	var buf bytes.Buffer
+	m := &macroTestModel{}
+	p := NewProgram(m, WithInput(nil), WithOutput(&buf), WithKeyRecording())
+	m.p = p
+
+	go func() {
+		for _, r := range "qabbq@" {
+			p.Send(KeyMsg{Type: KeyRunes, Runes: []rune{r}})
+		}
+	}()
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := m.keys, []string{"q", "a", "b", "b", "q", "@", "a", "b", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the model to process %q, got %q", want, got)
+	}
+	if len(m.macro) != 3 {
+		t.Fatalf("expected a macro of 3 keys, got %v", m.macro)
+	}
+	if got := p.RecordedKeys(); len(got) != len(m.keys) {
+		t.Fatalf("expected %d recorded keys, got %v", len(m.keys), got)
+	}
+}
@@ -200,7 +200,10 @@ func parseSGRMouseEvent(buf []byte) MouseEvent {
 	return m
 }
 
-const x10MouseByteOffset = 32
+const (
+	x10MouseByteOffset = 32
+	x10MouseMaxCoord   = 255 - x10MouseByteOffset
+)
 
 // Parse X10-encoded mouse events; the simplest kind. The last release of X10
 // was December 1986, by the way. The original X10 mouse protocol limits the Cx
@@ -215,13 +218,26 @@ func parseX10MouseEvent(buf []byte) MouseEvent {
 	v := buf[3:6]
 	m := parseMouseButton(int(v[0]), false)
 
-	// (1,1) is the upper left. We subtract 1 to normalize it to (0,0).
-	m.X = int(v[1]) - x10MouseByteOffset - 1
-	m.Y = int(v[2]) - x10MouseByteOffset - 1
+	m.X = parseX10MouseCoord(v[1])
+	m.Y = parseX10MouseCoord(v[2])
 
 	return m
 }
 
+// parseX10MouseCoord decodes an X10-encoded mouse coordinate.
+//
+// Positions beyond the 223 limit can't be encoded, and terminals report them
+// as a byte below the offset (usually 0) instead. We report those as being at
+// the limit rather than at a negative position.
+func parseX10MouseCoord(b byte) int {
+	if b <= x10MouseByteOffset {
+		return x10MouseMaxCoord - 1
+	}
+
+	// (1,1) is the upper left. We subtract 1 to normalize it to (0,0).
+	return int(b) - x10MouseByteOffset - 1
+}
+
 // See: https://invisible-island.net/xterm/ctlseqs/ctlseqs.html#h3-Extended-coordinates
 func parseMouseButton(b int, isSGR bool) MouseEvent {
 	var m MouseEvent
@@ -1,7 +1,9 @@
 package tea
 
 import (
+	"bytes"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -546,8 +548,8 @@ func TestParseX10MouseEvent(t *testing.T) {
 			name: "overflow position",
 			buf:  encode(0b0010_0000, 250, 223), // Because 255 (max int8) - 32 - 1.
 			expected: MouseEvent{
-				X:      -6,
-				Y:      -33,
+				X:      222,
+				Y:      222,
 				Type:   MouseLeft,
 				Action: MouseActionMotion,
 				Button: MouseButtonLeft,
@@ -571,6 +573,26 @@ func TestParseX10MouseEvent(t *testing.T) {
 	}
 }
 
+func TestReadX10MouseEvent(t *testing.T) {
+	// A left click at column 10, row 5, followed by a release at the
+	// coordinate limit and a keypress.
+	input := []byte{
+		'\x1b', '[', 'M', 32 + 0, 32 + 11, 32 + 6,
+		'\x1b', '[', 'M', 32 + 3, 255, 0,
+		'a',
+	}
+	msgs := testReadInputs(t, bytes.NewReader(input))
+
+	expected := []Msg{
+		MouseMsg{X: 10, Y: 5, Type: MouseLeft, Action: MouseActionPress, Button: MouseButtonLeft},
+		MouseMsg{X: 222, Y: 222, Type: MouseRelease, Action: MouseActionRelease, Button: MouseButtonNone},
+		KeyMsg{Type: KeyRunes, Runes: []rune{'a'}},
+	}
+	if !reflect.DeepEqual(msgs, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, msgs)
+	}
+}
+
 // func TestParseX10MouseEvent_error(t *testing.T) {
 // 	tt := []struct {
 // 		name string
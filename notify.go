@@ -0,0 +1,60 @@
+package tea
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// NotificationFormat is the escape sequence used to raise desktop
+// notifications with Notify, see WithNotificationFormat.
+type NotificationFormat int
+
+// Available notification formats.
+const (
+	// NotificationOSC9 uses OSC 9, which is supported by iTerm2, WezTerm,
+	// Windows Terminal and others. It only carries the body of a
+	// notification, so the title is left out.
+	NotificationOSC9 NotificationFormat = iota
+
+	// NotificationOSC777 uses OSC 777, which is supported by rxvt-unicode,
+	// foot, Ghostty and VTE based terminals, among others.
+	NotificationOSC777
+)
+
+// notifyMsg is an internal message used to raise a desktop notification.
+type notifyMsg struct {
+	title string
+	body  string
+}
+
+// Notify produces a command that asks the terminal to raise a desktop
+// notification, so the user learns about an event, such as a build
+// completing, while working in another window. Terminals that don't support
+// notifications ignore it. Control characters are removed from the title
+// and body so they can't end the sequence early.
+//
+// The escape sequence used can be chosen with [WithNotificationFormat].
+//
+// For example:
+//
+//	case buildDoneMsg:
+//	    return m, tea.Notify("Build complete", "All 42 packages built")
+func Notify(title, body string) Cmd {
+	return func() Msg {
+		return notifyMsg{title: title, body: body}
+	}
+}
+
+// notificationSequence returns the sequence raising a notification in the
+// given format.
+func notificationSequence(format NotificationFormat, title, body string) string {
+	title, body = sanitizeTitle(title), sanitizeTitle(body)
+	if format == NotificationOSC777 {
+		// The title is followed by the body, so it can't contain the
+		// separator.
+		title = strings.ReplaceAll(title, ";", ",")
+		return "\x1b]777;notify;" + title + ";" + body + "\x07"
+	}
+	return ansi.Notify(body)
+}
@@ -3,7 +3,10 @@ package tea
 import (
 	"context"
 	"io"
+	"net"
+	"os"
 	"sync/atomic"
+	"time"
 )
 
 // ProgramOption is used to set options when initializing a Program. Program can
@@ -27,6 +30,9 @@ func WithContext(ctx context.Context) ProgramOption {
 // won't need to use this.
 func WithOutput(output io.Writer) ProgramOption {
 	return func(p *Program) {
+		if p.startupOptions.has(withConn) {
+			p.conflict("WithConn", "WithOutput")
+		}
 		p.output = output
 	}
 }
@@ -37,14 +43,44 @@ func WithOutput(output io.Writer) ProgramOption {
 //	p := NewProgram(model, WithInput(nil))
 func WithInput(input io.Reader) ProgramOption {
 	return func(p *Program) {
+		if p.startupOptions.has(withConn) {
+			p.conflict("WithConn", "WithInput")
+		}
 		p.input = input
 		p.inputType = customInput
 	}
 }
 
+// WithConn uses the given network connection for both input and output. This
+// is useful for serving programs over TCP or similar transports.
+//
+// When the connection is closed by the peer the program quits. Other errors
+// reading from the connection are sent to the program as an [InputErrorMsg].
+// The connection is not closed when the program exits.
+func WithConn(conn net.Conn) ProgramOption {
+	return func(p *Program) {
+		switch p.inputType {
+		case customInput:
+			p.conflict("WithConn", "WithInput")
+		case ttyInput:
+			p.conflict("WithConn", "WithInputTTY")
+		}
+		if p.output != nil {
+			p.conflict("WithConn", "WithOutput")
+		}
+		p.input = conn
+		p.inputType = customInput
+		p.output = conn
+		p.startupOptions |= withConn
+	}
+}
+
 // WithInputTTY opens a new TTY for input (or console input device on Windows).
 func WithInputTTY() ProgramOption {
 	return func(p *Program) {
+		if p.startupOptions.has(withConn) {
+			p.conflict("WithConn", "WithInputTTY")
+		}
 		p.inputType = ttyInput
 	}
 }
@@ -54,6 +90,11 @@ func WithInputTTY() ProgramOption {
 // you want to pass the environment variables from the remote session to the
 // program.
 //
+// The renderer honors NO_COLOR, CLICOLOR and CLICOLOR_FORCE from the
+// environment: with NO_COLOR set, or CLICOLOR set to 0 without CLICOLOR_FORCE,
+// colors are stripped from the view and printed lines. NO_COLOR takes
+// precedence over CLICOLOR_FORCE.
+//
 // Example:
 //
 //	var sess ssh.Session // ssh.Session is a type from the github.com/charmbracelet/ssh package
@@ -74,6 +115,45 @@ func WithoutSignalHandler() ProgramOption {
 	}
 }
 
+// WithSignalMap changes the messages sent to the program when it receives
+// signals. By default, SIGINT sends an InterruptMsg and SIGTERM sends a
+// QuitMsg; signals missing from the map keep their default. Other signals can
+// be added, for example to map SIGQUIT to a custom message. The program
+// stops listening for signals after sending a QuitMsg or InterruptMsg.
+//
+//	p := tea.NewProgram(model, tea.WithSignalMap(map[os.Signal]func() tea.Msg{
+//	    syscall.SIGTERM: tea.Interrupt,
+//	}))
+//
+// It has no effect with WithoutSignalHandler.
+func WithSignalMap(m map[os.Signal]func() Msg) ProgramOption {
+	return func(p *Program) {
+		p.signalMap = m
+	}
+}
+
+// WithoutResizeHandler disables the handler that Bubble Tea sets up to detect
+// window resizes. This is useful when embedding a program in an application
+// that already watches for resizes, which can then report the size with
+// Program.SetSize. The size of the window is still queried on startup.
+func WithoutResizeHandler() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withoutResizeHandler
+	}
+}
+
+// WithLazyRawMode defers reading input, and with it putting the terminal into
+// raw mode, until the commands returned by Init have run, rather than doing so
+// on startup. Programs that quit from Init never enter raw mode, and those
+// that hand over the terminal with Exec only do so once the command returns.
+// Until then, keys typed are buffered by the terminal. The terminal is
+// restored on exit as usual.
+func WithLazyRawMode() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withLazyRawMode
+	}
+}
+
 // WithoutCatchPanics disables the panic catching that Bubble Tea does by
 // default. If panic catching is disabled the terminal will be in a fairly
 // unusable state after a panic because Bubble Tea will not perform its usual
@@ -112,6 +192,108 @@ func WithAltScreen() ProgramOption {
 	}
 }
 
+// WithFullHeightInline renders the program using the full height of the
+// terminal without entering the alternate screen buffer. Every frame is padded
+// to the current window height, so the program behaves much like a full
+// window application while keeping the terminal's scrollback history intact
+// once it exits.
+//
+// This has no effect while the alt screen is active.
+func WithFullHeightInline() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withFullHeightInline
+	}
+}
+
+// WithSoftWrap wraps lines of the view that are wider than the terminal onto
+// as many rows as they need, instead of cutting them off at the edge of the
+// window. Lines are broken between graphemes, so escape sequences and wide
+// characters are never split. As wrapped lines take up more rows, the layout
+// of the view depends on the width of the terminal, which is why this isn't
+// the default.
+//
+// This has no effect when rendering into a viewport, see WithViewport.
+func WithSoftWrap() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withSoftWrap
+	}
+}
+
+// WithSmartRepaint avoids repainting the screen after the terminal is
+// resized when the frame looks the same at the new size, for instance when
+// the window grows wider than the program's output. By default, every resize
+// causes a full repaint.
+func WithSmartRepaint() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withSmartRepaint
+	}
+}
+
+// WithDeterministicBatch executes the commands of a [Batch] one after another,
+// in the order they were given, instead of concurrently. Each command still
+// produces its own message, so this differs from [Sequence] only in that
+// nested batches are executed in order too. This makes the order of messages
+// reproducible, which is useful for golden tests.
+func WithDeterministicBatch() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withDeterministicBatch
+	}
+}
+
+// WithAutoResetSGR resets text attributes at the end of every frame that
+// leaves some set, for instance a view ending in a color without a reset.
+// Otherwise, the attributes bleed into the rest of the terminal, including the
+// shell prompt after the program exits.
+func WithAutoResetSGR() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withAutoResetSGR
+	}
+}
+
+// WithLatestWindowSizeOnly coalesces window sizes waiting to be processed, so
+// that Update only sees the latest one. This saves models that do expensive
+// work on resize from going through every intermediate size of a burst of
+// resizes, for instance while the user drags the window border. Only window
+// sizes sent with Send, which includes those reported on SIGWINCH, are
+// coalesced.
+func WithLatestWindowSizeOnly() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withLatestWindowSizeOnly
+	}
+}
+
+// WithSynchronizedOutput wraps every frame in synchronized output sequences
+// (mode 2026), which tells supporting terminals to render the frame at once.
+// This avoids tearing when frames are rendered quickly. Terminals that don't
+// support synchronized output ignore the sequences.
+func WithSynchronizedOutput() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withSynchronizedOutput
+	}
+}
+
+// WithSynchronizedOutputIfSupported is like [WithSynchronizedOutput], but
+// asks the terminal whether it supports synchronized output (with DECRQM)
+// first. Frames are only wrapped in synchronized output sequences once the
+// terminal reported support; until then, and if it doesn't respond, they're
+// rendered as usual. Use it for terminals that show the sequences rather
+// than ignoring them.
+func WithSynchronizedOutputIfSupported() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withSynchronizedOutputIfSupported
+	}
+}
+
+// WithKeepOutputOnExit leaves the final view on the screen when the program
+// quits, with the cursor below it, instead of erasing it. This is useful to
+// leave a summary behind. It has no effect when the program is killed, or
+// when it quits in the alternate screen buffer.
+func WithKeepOutputOnExit() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withKeepOutputOnExit
+	}
+}
+
 // WithoutBracketedPaste starts the program with bracketed paste disabled.
 func WithoutBracketedPaste() ProgramOption {
 	return func(p *Program) {
@@ -180,6 +362,108 @@ func WithoutRenderer() ProgramOption {
 	}
 }
 
+// WithSimpleRenderer replaces the standard renderer with a fallback one that
+// prints every new view below the previous one instead of redrawing it in
+// place, without any cursor movement. It's meant for terminals the standard
+// renderer misbehaves in, such as some CI environments, and for debugging
+// rendering issues. The simple renderer doesn't support the alternate screen,
+// mouse, bracketed paste or focus reporting, nor setting the window title.
+func WithSimpleRenderer() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withSimpleRenderer
+	}
+}
+
+// WithContinuousRendering delivers a RenderTickMsg to Update at the frame
+// rate, see WithFPS, even when nothing else happens. Views that depend on time,
+// such as animations, then keep being rendered without the model having to
+// schedule its own ticks. Frames that didn't change are still skipped by the
+// renderer.
+func WithContinuousRendering() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withContinuousRendering
+	}
+}
+
+// WithSynchronousInit runs the command returned by the model's Init, and has
+// the model process its messages, before rendering the first frame. The first
+// frame then reflects what Init loaded, such as configuration, rather than
+// briefly showing the model's initial state. Batches and sequences are run the
+// same way, while commands returned by Update run as usual.
+//
+// Commands that haven't returned after a second, for instance because they
+// wait on input, which isn't read before the first frame, are left to run
+// asynchronously so the program doesn't hang.
+func WithSynchronousInit() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withSynchronousInit
+	}
+}
+
+// WithStartupBanner prints the line returned by fn above the program when it
+// starts, before the first frame, like [Println]. Use it to put context such
+// as the version, the process ID or the terminal's capabilities into the
+// scrollback, so it ends up in the output users paste into bug reports:
+//
+//	p := tea.NewProgram(model, tea.WithStartupBanner(func() string {
+//	    return fmt.Sprintf("myapp %s (pid %d)", version, os.Getpid())
+//	}))
+//
+// Like Println, the banner isn't printed when the program starts in the
+// alternate screen buffer or without a renderer.
+func WithStartupBanner(fn func() string) ProgramOption {
+	return func(p *Program) {
+		p.startupBanner = fn
+	}
+}
+
+// WithInitialCmds runs the given commands at startup, alongside the command
+// returned by the model's Init. This lets the code launching the program kick
+// off work the model shouldn't have to know about, such as loading data from a
+// source chosen by the caller:
+//
+//	p := tea.NewProgram(model, tea.WithInitialCmds(loadConfig(path)))
+//
+// Like Init's command, the commands run concurrently with the program and
+// their messages are delivered to Update. Nil commands are ignored.
+func WithInitialCmds(cmds ...Cmd) ProgramOption {
+	return func(p *Program) {
+		p.initialCmds = append(p.initialCmds, cmds...)
+	}
+}
+
+// WithSlowOutputThreshold sends the program a [SlowOutputMsg] whenever
+// writing to the output takes longer than d, so it can reduce the work done to
+// render frames when the output can't keep up. Writes to terminals take well
+// under a millisecond, so a threshold of, say, 100 milliseconds only catches
+// outputs that are actually stalled. A stalled write can hold up the event
+// loop, so the message may arrive only once the write completes. By default,
+// slow writes aren't reported.
+func WithSlowOutputThreshold(d time.Duration) ProgramOption {
+	return func(p *Program) {
+		p.slowOutputThreshold = d
+	}
+}
+
+// WithStrictModel stops the program with [ErrNilModel] when the model's
+// Update returns a nil Model. By default, the program keeps the previous
+// model and reports ErrNilModel on [Program.Errors] instead.
+func WithStrictModel() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withStrictModel
+	}
+}
+
+// WithKeyRecording records every key processed by the model, after
+// filtering with [WithFilter], so they can be retrieved with
+// Program.RecordedKeys. The recording grows for as long as the program runs.
+// Recording macros with Program.StartMacro doesn't need this option.
+func WithKeyRecording() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withKeyRecording
+	}
+}
+
 // WithANSICompressor removes redundant ANSI sequences to produce potentially
 // smaller output, at the cost of some processing overhead.
 //
@@ -229,6 +513,186 @@ func WithFilter(filter func(Model, Msg) Msg) ProgramOption {
 	}
 }
 
+// WithErrorHandler sets a function that's called with the error of every
+// ErrorMsg once the model's Update has returned, whether or not the model did
+// anything with it. This gives a single place to deal with errors the same
+// way across a program, such as logging them or quitting. The command it
+// returns, if any, runs alongside the one returned by Update. ErrorMsgs
+// without an error are skipped.
+//
+//	p := tea.NewProgram(Model{}, tea.WithErrorHandler(func(err error) tea.Cmd {
+//		log.Printf("error: %v", err)
+//		return nil
+//	}))
+func WithErrorHandler(handler func(error) Cmd) ProgramOption {
+	return func(p *Program) {
+		p.errorHandler = handler
+	}
+}
+
+// WithInputInterceptor sets a function that sees the raw input before it's
+// parsed into keys and mouse events, to recognize custom sequences such as the
+// protocol of a hardware device. It's called with the input left to parse and
+// returns the number of bytes it consumed, if any, along with the messages to
+// deliver for them. Input it doesn't consume is parsed as usual.
+//
+//	tea.WithInputInterceptor(func(raw []byte) (int, []tea.Msg) {
+//		if bytes.HasPrefix(raw, []byte{0xfe, 0x01}) {
+//			return 2, []tea.Msg{buttonMsg{}}
+//		}
+//		return 0, nil
+//	})
+//
+// Input may arrive in pieces, so a sequence may be split across calls. On
+// Windows, it only applies when reading input that isn't the console.
+func WithInputInterceptor(fn func(raw []byte) (consumed int, msgs []Msg)) ProgramOption {
+	return func(p *Program) {
+		p.inputInterceptor = fn
+	}
+}
+
+// defaultEscapeTimeout is how long to wait for the rest of an escape sequence
+// by default.
+const defaultEscapeTimeout = 50 * time.Millisecond
+
+// WithEscapeTimeout sets how long to wait for the rest of an escape sequence
+// after reading an escape character. Terminals report the escape key as a
+// lone escape character, which is also how the sequences for keys like the
+// arrow keys begin. If the rest of a sequence arrives in time, the whole
+// sequence is reported as a single key; otherwise, the escape key is. Slow
+// connections may need a longer timeout, at the cost of a slower escape key.
+//
+// The default is 50 milliseconds. A timeout of 0 disables waiting, in which
+// case an escape character at the end of a read is always the escape key.
+// On Windows, it only applies when reading input that isn't the console.
+func WithEscapeTimeout(d time.Duration) ProgramOption {
+	return func(p *Program) {
+		p.escapeTimeout = d
+	}
+}
+
+// WithSlowUpdateWarning times every call to the model's Update and calls fn
+// with the type of the message and how long it took when it takes longer than
+// d. Slow updates delay rendering and input handling, so this helps finding
+// handlers causing jank. The program keeps running regardless.
+//
+//	tea.WithSlowUpdateWarning(16*time.Millisecond, func(msgType string, took time.Duration) {
+//		log.Printf("slow update: %s took %s", msgType, took)
+//	})
+//
+// fn is called from the event loop, so it should return quickly.
+func WithSlowUpdateWarning(d time.Duration, fn func(msgType string, took time.Duration)) ProgramOption {
+	return func(p *Program) {
+		p.slowUpdateThreshold = d
+		p.slowUpdateWarning = fn
+	}
+}
+
+// WithStatusLine pins the line returned by fn to the bottom of every frame,
+// independent of the model's view, much like the status bar of tmux. In the
+// alt screen it sits on the last row of the window; inline it's the last line
+// of the frame. The view gets one row less to keep the status line in view.
+//
+// fn is called from the renderer each time it renders a frame, so it should
+// return quickly. Only the first line of what it returns is shown. Frames are
+// rendered when the view changes, so use [WithContinuousRendering] for a status
+// line that changes on its own, such as a clock.
+//
+// The status line isn't shown in a viewport set with [WithViewport].
+func WithStatusLine(fn func() string) ProgramOption {
+	return func(p *Program) {
+		p.statusLine = fn
+	}
+}
+
+// WithOriginMode makes the renderer set origin mode (DECOM) while painting
+// into the scroll areas of [SyncScrollArea], [ScrollUp] and [ScrollDown] if
+// enabled is true, or explicitly reset it if enabled is false. In origin mode,
+// cursor positions are relative to the top margin of the scroll area, rather
+// than to the top of the window.
+//
+// Without this option origin mode is left as the terminal has it, which
+// offsets the scroll area by its margin in terminals where something else left
+// it set. With either setting, origin mode is reset once the renderer is done
+// painting.
+func WithOriginMode(enabled bool) ProgramOption {
+	return func(p *Program) {
+		p.originMode = &enabled
+	}
+}
+
+// WithNotificationFormat sets the escape sequence used to raise desktop
+// notifications with [Notify]. Terminals support different sequences, so
+// pick the one supported by the terminals your users are likely to use. The
+// default is [NotificationOSC9].
+func WithNotificationFormat(format NotificationFormat) ProgramOption {
+	return func(p *Program) {
+		p.notificationFormat = format
+	}
+}
+
+// WithUnmatchedResponseHook sets a function that's called with responses to
+// terminal queries the program didn't make, or gave up waiting for, such as a
+// cursor position report requested by another program sharing the terminal.
+// Responses to queries never reach the model, whether they're matched or not.
+//
+// The response is passed as an escape sequence re-encoded from its parsed
+// form. fn is called from the event loop, so it should return quickly.
+//
+// Note that some cursor position reports look exactly like function keys with
+// modifiers, and are delivered as such.
+func WithUnmatchedResponseHook(fn func(seq string)) ProgramOption {
+	return func(p *Program) {
+		p.unmatchedResponseHook = fn
+	}
+}
+
+// WithOutputBuffer buffers up to size bytes of the renderer's output and
+// writes it once per frame, rather than writing each escape sequence as it's
+// sent. This saves system calls and packets on slow outputs, such as network
+// connections. Sequences sent by commands are then written with the next
+// frame, at the latest after one frame's time, see WithFPS. The buffer is
+// written out before the program exits.
+func WithOutputBuffer(size int) ProgramOption {
+	return func(p *Program) {
+		p.outputBufferSize = size
+	}
+}
+
+// WithCommandTimeout abandons commands created with [CmdWithContext] that
+// haven't returned a message within d: their context is canceled, their
+// message is dropped if they ever return one, and a [CommandTimeoutMsg] is
+// sent to the model instead. This keeps a single hung command, such as a
+// request to an unresponsive server, from being waited on forever.
+//
+// Other commands aren't subject to the timeout, as many of them, such as Tick
+// and Every, block on purpose. Note that Go offers no way to interrupt a
+// function, so an abandoned command that ignores its context keeps running in
+// the background until it returns.
+func WithCommandTimeout(d time.Duration) ProgramOption {
+	return func(p *Program) {
+		p.commandTimeout = d
+	}
+}
+
+// WithRecoveryModel keeps the program running when the model panics in Update
+// or View: the model is replaced with the one returned by fn, which gets the
+// panic value, for instance to show an error screen until the user quits. Its
+// Init is called as usual. If the recovery model panics too, the program stops
+// with ErrProgramPanic, as it does without this option.
+//
+//	tea.WithRecoveryModel(func(r interface{}) tea.Model {
+//		return errorScreen{err: fmt.Errorf("unexpected error: %v", r)}
+//	})
+//
+// This doesn't apply to panics in commands, nor if catching panics is
+// disabled with WithoutCatchPanics.
+func WithRecoveryModel(fn func(r interface{}) Model) ProgramOption {
+	return func(p *Program) {
+		p.recoveryModel = fn
+	}
+}
+
 // WithFPS sets a custom maximum FPS at which the renderer should run. If
 // less than 1, the default value of 60 will be used. If over 120, the FPS
 // will be capped at 120.
@@ -238,6 +702,61 @@ func WithFPS(fps int) ProgramOption {
 	}
 }
 
+// WithFrameLog writes every frame rendered by the program to w, annotated
+// with a frame counter and timestamp and escaped for readability. This is
+// useful to inspect what the renderer produced over the course of a session.
+func WithFrameLog(w io.Writer) ProgramOption {
+	return func(p *Program) {
+		p.frameLog = w
+	}
+}
+
+// WithMaxWidth caps the width of the frames rendered by the program. Lines
+// wider than the given number of cells are truncated, even if the terminal is
+// wider. The cap can be lifted temporarily with [MaximizeViewport].
+func WithMaxWidth(width int) ProgramOption {
+	return func(p *Program) {
+		p.maxWidth = width
+	}
+}
+
+// WithViewport renders the program into the given rectangle of the terminal,
+// rather than at the cursor, leaving the rest of the terminal untouched. x and
+// y are the zero-based column and row of the top left corner. Frames are
+// clipped to the rectangle, and window size messages report its size instead
+// of the terminal's.
+//
+// This is meant for hosts that manage the layout of the terminal themselves,
+// for instance to embed a program in a larger UI. Println and Printf have no
+// effect in a viewport.
+//
+// The width and height must be positive and x and y can't be negative,
+// otherwise Run fails with ErrInvalidViewport.
+func WithViewport(x, y, width, height int) ProgramOption {
+	return func(p *Program) {
+		p.region = &region{x: x, y: y, width: width, height: height}
+	}
+}
+
+// WithKeyRepeatThrottle drops keys that repeat the previous key within d,
+// which smooths out scrolling and such while a key is held down on terminals
+// that repeat keys rapidly. Distinct keys are never dropped, nor are pastes.
+func WithKeyRepeatThrottle(d time.Duration) ProgramOption {
+	return func(p *Program) {
+		p.keyRepeatThrottle = d
+	}
+}
+
+// WithMinimumSize sets the minimum terminal size the program needs. While the
+// terminal is smaller, the model receives a TooSmallMsg instead of a
+// WindowSizeMsg. A width or height of zero isn't checked.
+func WithMinimumSize(width, height int) ProgramOption {
+	return func(p *Program) {
+		p.minWidth = width
+		p.minHeight = height
+	}
+}
+
 // WithReportFocus enables reporting when the terminal gains and loses
 // focus. When this is enabled [FocusMsg] and [BlurMsg] messages will be sent
 // to your Update method.
@@ -250,3 +769,86 @@ func WithReportFocus() ProgramOption {
 		p.startupOptions |= withReportFocus
 	}
 }
+
+// WithLoadingView sets a view that is rendered while the model isn't ready to
+// be rendered yet. It only has an effect on models implementing [ModelReady].
+// Without a loading view, nothing is rendered until the model is ready.
+func WithLoadingView(view string) ProgramOption {
+	return func(p *Program) {
+		p.loadingView = view
+	}
+}
+
+// WithViewTransform sets a function that transforms the model's view before
+// it's rendered, on every render. This can be used to add a border, header or
+// watermark to every view without modifying the models.
+func WithViewTransform(fn func(view string) string) ProgramOption {
+	return func(p *Program) {
+		p.viewTransform = fn
+	}
+}
+
+// WithDroppedMessageHook sets a function that is called with every message
+// dropped by [Program.TrySend] because the program couldn't keep up. This is
+// useful to gain visibility into overload. The hook is called from the
+// goroutine calling TrySend and should return quickly.
+func WithDroppedMessageHook(fn func(Msg)) ProgramOption {
+	return func(p *Program) {
+		p.droppedMessageHook = fn
+	}
+}
+
+// WithDebugOverlay renders a small diagnostic line in the bottom-right corner
+// of every frame, showing the number of messages processed and frames
+// rendered per second, as well as the type of the last message. The model is
+// unaware of the overlay, which only overwrites the corner it occupies.
+//
+// This is intended for use during development.
+func WithDebugOverlay() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withDebugOverlay
+	}
+}
+
+// WithGlobalPanicRestore guards the terminal against the host application
+// terminating outside of Bubble Tea's control. While the program is running,
+// its terminal is restored when:
+//
+//   - a goroutine that deferred [RecoverTerminal] panics, or
+//   - the process receives SIGQUIT (not available on Windows).
+//
+// Note that nothing can be done when the process exits through [os.Exit], is
+// killed with SIGKILL, or panics in a goroutine that didn't defer
+// RecoverTerminal.
+func WithGlobalPanicRestore() ProgramOption {
+	return func(p *Program) {
+		p.startupOptions |= withGlobalPanicRestore
+	}
+}
+
+// WithSnapshotDepth sets the maximum number of snapshots kept by
+// [PushSnapshot]. If less than 1, the default of 64 is used.
+func WithSnapshotDepth(depth int) ProgramOption {
+	return func(p *Program) {
+		p.snapshotDepth = depth
+	}
+}
+
+// conflict records that two options contradict each other. Run fails with
+// ErrConflictingOptions if any did.
+func (p *Program) conflict(a, b string) {
+	p.optionConflicts = append(p.optionConflicts, a+" and "+b)
+}
+
+// checkOptions records conflicts between options that don't depend on the
+// order they're given in. It's called once all options are applied.
+func (p *Program) checkOptions() {
+	if _, ok := p.renderer.(*nilRenderer); ok {
+		if p.startupOptions.has(withAltScreen) {
+			p.conflict("WithoutRenderer", "WithAltScreen")
+		}
+		if p.startupOptions.has(withSimpleRenderer) {
+			p.conflict("WithoutRenderer", "WithSimpleRenderer")
+		}
+	}
+}
@@ -3,7 +3,10 @@ package tea
 import (
 	"bytes"
 	"context"
+	"errors"
+	"net"
 	"os"
+	"strings"
 	"sync/atomic"
 	"testing"
 )
@@ -52,6 +55,60 @@ func TestOptions(t *testing.T) {
 		}
 	})
 
+	t.Run("loading view", func(t *testing.T) {
+		p := NewProgram(nil, WithLoadingView("loading"))
+		if p.loadingView != "loading" {
+			t.Errorf("expected loading view to be set, got %q", p.loadingView)
+		}
+	})
+
+	t.Run("frame log", func(t *testing.T) {
+		var buf bytes.Buffer
+		p := NewProgram(nil, WithFrameLog(&buf))
+		if p.frameLog != &buf {
+			t.Errorf("expected frame log to be set")
+		}
+	})
+
+	t.Run("view transform", func(t *testing.T) {
+		p := NewProgram(nil, WithViewTransform(func(view string) string {
+			return view
+		}))
+		if p.viewTransform == nil {
+			t.Errorf("expected view transform to be set")
+		}
+	})
+
+	t.Run("viewport", func(t *testing.T) {
+		p := NewProgram(nil, WithViewport(1, 2, 30, 10))
+		if expected := (region{x: 1, y: 2, width: 30, height: 10}); p.region == nil || *p.region != expected {
+			t.Errorf("expected viewport %+v, got %+v", expected, p.region)
+		}
+	})
+
+	t.Run("origin mode", func(t *testing.T) {
+		p := NewProgram(nil, WithOriginMode(true))
+		if p.originMode == nil || !*p.originMode {
+			t.Errorf("expected origin mode to be enabled")
+		}
+	})
+
+	t.Run("initial cmds", func(t *testing.T) {
+		p := NewProgram(nil, WithInitialCmds(Quit), WithInitialCmds(nil, Quit))
+		if len(p.initialCmds) != 3 {
+			t.Errorf("expected 3 initial commands, got %d", len(p.initialCmds))
+		}
+	})
+
+	t.Run("escape timeout", func(t *testing.T) {
+		if p := NewProgram(nil); p.escapeTimeout != defaultEscapeTimeout {
+			t.Errorf("expected the default escape timeout, got %v", p.escapeTimeout)
+		}
+		if p := NewProgram(nil, WithEscapeTimeout(0)); p.escapeTimeout != 0 {
+			t.Errorf("expected no escape timeout, got %v", p.escapeTimeout)
+		}
+	})
+
 	t.Run("external context", func(t *testing.T) {
 		extCtx, extCancel := context.WithCancel(context.Background())
 		defer extCancel()
@@ -108,6 +165,85 @@ func TestOptions(t *testing.T) {
 			exercise(t, WithoutSignalHandler(), withoutSignalHandler)
 		})
 
+		t.Run("without resize handler", func(t *testing.T) {
+			exercise(t, WithoutResizeHandler(), withoutResizeHandler)
+		})
+
+		t.Run("lazy raw mode", func(t *testing.T) {
+			exercise(t, WithLazyRawMode(), withLazyRawMode)
+		})
+
+		t.Run("auto reset sgr", func(t *testing.T) {
+			exercise(t, WithAutoResetSGR(), withAutoResetSGR)
+		})
+
+		t.Run("latest window size only", func(t *testing.T) {
+			exercise(t, WithLatestWindowSizeOnly(), withLatestWindowSizeOnly)
+		})
+
+		t.Run("simple renderer", func(t *testing.T) {
+			exercise(t, WithSimpleRenderer(), withSimpleRenderer)
+		})
+
+		t.Run("continuous rendering", func(t *testing.T) {
+			exercise(t, WithContinuousRendering(), withContinuousRendering)
+		})
+
+		t.Run("synchronous init", func(t *testing.T) {
+			exercise(t, WithSynchronousInit(), withSynchronousInit)
+		})
+
+		t.Run("strict model", func(t *testing.T) {
+			exercise(t, WithStrictModel(), withStrictModel)
+		})
+
+		t.Run("key recording", func(t *testing.T) {
+			exercise(t, WithKeyRecording(), withKeyRecording)
+		})
+
+		t.Run("full height inline", func(t *testing.T) {
+			exercise(t, WithFullHeightInline(), withFullHeightInline)
+		})
+
+		t.Run("debug overlay", func(t *testing.T) {
+			exercise(t, WithDebugOverlay(), withDebugOverlay)
+		})
+
+		t.Run("global panic restore", func(t *testing.T) {
+			exercise(t, WithGlobalPanicRestore(), withGlobalPanicRestore)
+		})
+
+		t.Run("deterministic batch", func(t *testing.T) {
+			exercise(t, WithDeterministicBatch(), withDeterministicBatch)
+		})
+
+		t.Run("conn", func(t *testing.T) {
+			server, client := net.Pipe()
+			defer server.Close() //nolint:errcheck
+			defer client.Close() //nolint:errcheck
+			exercise(t, WithConn(server), withConn)
+		})
+
+		t.Run("keep output on exit", func(t *testing.T) {
+			exercise(t, WithKeepOutputOnExit(), withKeepOutputOnExit)
+		})
+
+		t.Run("synchronized output", func(t *testing.T) {
+			exercise(t, WithSynchronizedOutput(), withSynchronizedOutput)
+		})
+
+		t.Run("synchronized output if supported", func(t *testing.T) {
+			exercise(t, WithSynchronizedOutputIfSupported(), withSynchronizedOutputIfSupported)
+		})
+
+		t.Run("soft wrap", func(t *testing.T) {
+			exercise(t, WithSoftWrap(), withSoftWrap)
+		})
+
+		t.Run("smart repaint", func(t *testing.T) {
+			exercise(t, WithSmartRepaint(), withSmartRepaint)
+		})
+
 		t.Run("mouse cell motion", func(t *testing.T) {
 			p := NewProgram(nil, WithMouseAllMotion(), WithMouseCellMotion())
 			if !p.startupOptions.has(withMouseCellMotion) {
@@ -141,3 +277,60 @@ func TestOptions(t *testing.T) {
 		}
 	})
 }
+
+func TestInvalidViewport(t *testing.T) {
+	for _, vp := range [][4]int{
+		{0, 0, 0, 10},
+		{0, 0, 30, 0},
+		{0, 0, -1, 10},
+		{0, 0, 30, -5},
+		{-1, 0, 30, 10},
+		{0, -1, 30, 10},
+	} {
+		p := NewProgram(&testModel{}, WithInput(&bytes.Buffer{}), WithOutput(&bytes.Buffer{}),
+			WithViewport(vp[0], vp[1], vp[2], vp[3]))
+		if _, err := p.Run(); !errors.Is(err, ErrInvalidViewport) {
+			t.Errorf("expected ErrInvalidViewport for the viewport %v, got %v", vp, err)
+		}
+	}
+}
+
+func TestConflictingOptions(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close() //nolint:errcheck
+	defer client.Close() //nolint:errcheck
+
+	tests := []struct {
+		name     string
+		opts     []ProgramOption
+		conflict string
+	}{
+		{
+			name:     "without renderer and alt screen",
+			opts:     []ProgramOption{WithAltScreen(), WithoutRenderer()},
+			conflict: "WithoutRenderer and WithAltScreen",
+		},
+		{
+			name:     "input after conn",
+			opts:     []ProgramOption{WithConn(server), WithInput(&bytes.Buffer{})},
+			conflict: "WithConn and WithInput",
+		},
+		{
+			name:     "output before conn",
+			opts:     []ProgramOption{WithOutput(&bytes.Buffer{}), WithConn(server)},
+			conflict: "WithConn and WithOutput",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProgram(&testModel{}, tt.opts...)
+			_, err := p.Run()
+			if !errors.Is(err, ErrConflictingOptions) {
+				t.Fatalf("expected ErrConflictingOptions, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.conflict) {
+				t.Fatalf("expected the error to describe %q, got %q", tt.conflict, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package tea
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// resetPaletteSeq is OSC 104 without parameters, which resets every entry of
+// the terminal's color palette to its default.
+const resetPaletteSeq = "\x1b]104\x07"
+
+// setPaletteColorMsg is an internal message used to change an entry of the
+// terminal's color palette.
+type setPaletteColorMsg struct {
+	index   int
+	r, g, b uint8
+}
+
+// SetPaletteColor produces a command that changes the color of entry index
+// of the terminal's 256 color palette with OSC 4, recoloring everything drawn
+// in it, such as text styled with ANSI color index. rgb is a color in the
+// "#rrggbb" form.
+//
+// The palette is reset when the program exits, see also [ResetPalette], and
+// while the terminal is released with [Program.ReleaseTerminal] or [Exec]; the
+// entries are set again once it's restored.
+// SetPaletteColor returns nil if index isn't between 0 and 255 or rgb isn't a
+// valid color. Terminals that don't support changing the palette ignore it.
+//
+//	// Use a softer red for errors.
+//	cmd := tea.SetPaletteColor(1, "#e06c75")
+func SetPaletteColor(index int, rgb string) Cmd {
+	if index < 0 || index > 255 || len(rgb) != 7 || rgb[0] != '#' {
+		return nil
+	}
+	c, err := strconv.ParseUint(rgb[1:], 16, 24)
+	if err != nil {
+		return nil
+	}
+	msg := setPaletteColorMsg{index: index, r: uint8(c >> 16), g: uint8(c >> 8), b: uint8(c)} //nolint:gosec
+	return func() Msg {
+		return msg
+	}
+}
+
+// sequence returns the OSC 4 sequence setting the palette entry.
+func (m setPaletteColorMsg) sequence() string {
+	return fmt.Sprintf("\x1b]4;%d;rgb:%02x/%02x/%02x\x07", m.index, m.r, m.g, m.b)
+}
+
+// ResetPalette is a special command that resets the terminal's color palette
+// to its defaults, undoing SetPaletteColor. It's done automatically when the
+// program exits.
+func ResetPalette() Msg {
+	return resetPaletteMsg{}
+}
+
+// resetPaletteMsg is an internal message that signals to reset the color
+// palette. You can send a resetPaletteMsg with ResetPalette.
+type resetPaletteMsg struct{}
@@ -0,0 +1,93 @@
+package tea
+
+import (
+	"bytes"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSetPaletteColor(t *testing.T) {
+	for _, tt := range []struct {
+		index int
+		rgb   string
+	}{
+		{-1, "#ff0000"},
+		{256, "#ff0000"},
+		{1, "ff0000"},
+		{1, "#f00"},
+		{1, "#gg0000"},
+		{1, "#+f0000"},
+	} {
+		if cmd := SetPaletteColor(tt.index, tt.rgb); cmd != nil {
+			t.Errorf("expected SetPaletteColor(%d, %q) to be rejected", tt.index, tt.rgb)
+		}
+	}
+
+	var in, out bytes.Buffer
+	p := NewProgram(viewTestModel("themed"), WithInput(&in), WithOutput(&out),
+		WithInitialCmds(Sequence(SetPaletteColor(1, "#E06C75"), Quit)))
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	set := strings.Index(got, "\x1b]4;1;rgb:e0/6c/75\x07")
+	if set < 0 {
+		t.Fatalf("expected the OSC 4 sequence, got %q", got)
+	}
+	if reset := strings.LastIndex(got, resetPaletteSeq); reset < set {
+		t.Fatalf("expected the palette to be reset on exit, got %q", got)
+	}
+}
+
+func TestStandardRendererResetPalette(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+
+	r.handleMessages(SetPaletteColor(255, "#000000")())
+	r.handleMessages(ResetPalette())
+	if want := "\x1b]4;255;rgb:00/00/00\x07" + resetPaletteSeq; out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+}
+
+type paletteExecTestModel struct{}
+
+func (m paletteExecTestModel) Init() Cmd {
+	return Sequence(SetPaletteColor(1, "#e06c75"), ExecProcess(exec.Command("true"), func(err error) Msg {
+		return execFinishedMsg{err}
+	}))
+}
+
+func (m paletteExecTestModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(execFinishedMsg); ok {
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m paletteExecTestModel) View() string {
+	return "themed"
+}
+
+func TestPaletteRestoredAfterExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("true is not available on windows")
+	}
+
+	var in, out bytes.Buffer
+	p := NewProgram(paletteExecTestModel{}, WithInput(&in), WithOutput(&out))
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Set, reset for the command, set again and reset on exit.
+	const set = "\x1b]4;1;rgb:e0/6c/75\x07"
+	seqs := regexp.MustCompile(regexp.QuoteMeta(set) + "|" + regexp.QuoteMeta(resetPaletteSeq))
+	got := strings.Join(seqs.FindAllString(out.String(), -1), "")
+	if want := set + resetPaletteSeq + set + resetPaletteSeq; got != want {
+		t.Fatalf("expected the palette to be set again after the command, got %q", out.String())
+	}
+}
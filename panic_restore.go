@@ -0,0 +1,90 @@
+package tea
+
+import (
+	"sync"
+	"time"
+)
+
+// panicRestoreTimeout is how long RecoverTerminal and the fatal signal handler
+// wait for a program to restore its terminal, as a program blocked in Update
+// can't shut down.
+const panicRestoreTimeout = time.Second
+
+// panicRestorePrograms are the running programs that opted into having their
+// terminal restored by RecoverTerminal and on fatal signals.
+var (
+	panicRestoreMtx      sync.Mutex
+	panicRestorePrograms = map[*Program]struct{}{}
+)
+
+// RecoverTerminal restores the terminal of every running [Program] started
+// with [WithGlobalPanicRestore] if the calling goroutine panics, then
+// re-panics. It must be deferred directly at the top of goroutines running
+// outside of Bubble Tea's control:
+//
+//	go func() {
+//	    defer tea.RecoverTerminal()
+//	    // ...
+//	}()
+//
+// Note that the stack trace printed when the process crashes is the one of
+// the re-panic, not of the original panic. Log [runtime/debug.Stack] in your
+// own deferred function before RecoverTerminal runs if you need it.
+//
+// Panics inside Update, View and commands are already handled by Bubble Tea,
+// unless [WithoutCatchPanics] is set.
+func RecoverTerminal() {
+	if r := recover(); r != nil {
+		restoreTerminals()
+		panic(r)
+	}
+}
+
+// restoreTerminals kills all programs registered for global panic restore and
+// waits for them to restore their terminals as part of their regular
+// shutdown.
+func restoreTerminals() {
+	panicRestoreMtx.Lock()
+	programs := make([]*Program, 0, len(panicRestorePrograms))
+	for p := range panicRestorePrograms {
+		programs = append(programs, p)
+	}
+	panicRestoreMtx.Unlock()
+
+	for _, p := range programs {
+		p.Kill()
+	}
+
+	timeout := time.After(panicRestoreTimeout)
+	for _, p := range programs {
+		select {
+		case <-p.finished:
+		case <-timeout:
+			return
+		}
+	}
+}
+
+// handlePanicRestore registers the program for global panic restore until it
+// exits, and listens for fatal signals.
+func (p *Program) handlePanicRestore() chan struct{} {
+	panicRestoreMtx.Lock()
+	panicRestorePrograms[p] = struct{}{}
+	panicRestoreMtx.Unlock()
+
+	ch := make(chan struct{})
+	go func() {
+		defer close(ch)
+		<-p.ctx.Done()
+
+		panicRestoreMtx.Lock()
+		delete(panicRestorePrograms, p)
+		panicRestoreMtx.Unlock()
+	}()
+
+	// The signal listener waits for the program to shut down, so it can't be
+	// one of the handlers the shutdown waits for.
+	go p.listenForFatalSignals()
+
+	return ch
+}
@@ -0,0 +1,78 @@
+package tea
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// syncBuffer is a bytes.Buffer that's safe for concurrent use.
+type syncBuffer struct {
+	mtx sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.String()
+}
+
+func TestRecoverTerminal(t *testing.T) {
+	var in bytes.Buffer
+	out := &syncBuffer{}
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(out), WithAltScreen(), WithGlobalPanicRestore())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+	waitForModelExecution(t, m)
+
+	// Simulate a panic in a goroutine of the host application.
+	recovered := make(chan interface{})
+	go func() {
+		defer func() { recovered <- recover() }()
+		defer RecoverTerminal()
+		panic("host panic")
+	}()
+
+	if r := <-recovered; r != "host panic" {
+		t.Fatalf("expected RecoverTerminal to re-panic, got %v", r)
+	}
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, ErrProgramKilled) {
+			t.Fatalf("expected ErrProgramKilled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("program did not stop after the host panicked")
+	}
+
+	got := out.String()
+	for _, seq := range []string{ansi.ResetAltScreenSaveCursorMode, ansi.ShowCursor, ansi.ResetBracketedPasteMode} {
+		if !strings.Contains(got, seq) {
+			t.Errorf("expected restore sequence %q in output %q", seq, got)
+		}
+	}
+
+	panicRestoreMtx.Lock()
+	defer panicRestoreMtx.Unlock()
+	if len(panicRestorePrograms) != 0 {
+		t.Errorf("expected no programs to remain registered, got %d", len(panicRestorePrograms))
+	}
+}
@@ -0,0 +1,118 @@
+package tea
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/muesli/cancelreader"
+)
+
+// readLineMsg is used internally to read a line of input with ReadLine.
+type readLineMsg struct {
+	prompt string
+	fn     func(string) Msg
+}
+
+// ReadLine produces a command that reads a single line of input the way a
+// plain command line tool would, which is handy for simple prompts, such as in
+// wizards. Like Exec, it pauses the program: the terminal is taken out of raw
+// mode, the prompt is printed, and the line is read with the terminal's own
+// line editing until enter is pressed. The terminal is then put back the way
+// it was and fn is called with the line, without the line ending, to produce
+// the message delivered to Update.
+//
+//	cmd := tea.ReadLine("Project name: ", func(name string) tea.Msg {
+//	    return projectNameMsg(name)
+//	})
+//
+// If the user presses ctrl+c while typing, fn is called with an empty line,
+// followed by an InterruptMsg.
+func ReadLine(prompt string, fn func(string) Msg) Cmd {
+	return func() Msg {
+		return readLineMsg{prompt: prompt, fn: fn}
+	}
+}
+
+// readLine reads a line of input with the terminal released and delivers the
+// result to the program.
+func (p *Program) readLine(msg readLineMsg) {
+	var line string
+	var interrupted bool
+	if err := p.ReleaseTerminal(); err == nil {
+		line, interrupted = p.readCookedLine(msg.prompt)
+
+		// Keep the prompt and the line on the screen.
+		p.renderer.resetLinesRendered()
+		_ = p.RestoreTerminal()
+	}
+
+	go func() {
+		if msg.fn != nil {
+			p.Send(msg.fn(line))
+		}
+		if interrupted {
+			p.Send(InterruptMsg{})
+		}
+	}()
+}
+
+// readCookedLine prints the prompt and reads a line of input. It reads a byte
+// at a time so it doesn't consume input past the line ending. It reports
+// whether the read was interrupted by ctrl+c.
+func (p *Program) readCookedLine(prompt string) (string, bool) {
+	if p.input == nil {
+		_, _ = io.WriteString(p.output, prompt)
+		return "", false
+	}
+
+	r, err := cancelreader.NewReader(p.input)
+	if err != nil {
+		return "", false
+	}
+	defer r.Close() //nolint:errcheck
+
+	// Out of raw mode, ctrl+c is turned into a SIGINT by the terminal.
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+
+	_, _ = io.WriteString(p.output, prompt)
+
+	interrupted := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-sig:
+			close(interrupted)
+			r.Cancel()
+		case <-p.ctx.Done():
+			r.Cancel()
+		case <-done:
+		}
+	}()
+
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	select {
+	case <-interrupted:
+		return "", true
+	default:
+		return strings.TrimSuffix(string(line), "\r"), false
+	}
+}
@@ -0,0 +1,50 @@
+package tea
+
+// update runs the model's Update. With WithRecoveryModel, a panic swaps in
+// the recovery model rather than stopping the program.
+func (p *Program) update(model Model, msg Msg) (m Model, cmd Cmd) {
+	if !p.canRecoverModel() {
+		return model.Update(msg)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			m, cmd = p.recoverModel(r)
+		}
+	}()
+	return model.Update(msg)
+}
+
+// renderOrRecover renders the model. With WithRecoveryModel, a panic in its
+// View swaps in the recovery model, which is rendered and returned instead,
+// along with the command returned by its Init.
+func (p *Program) renderOrRecover(model Model) (m Model, cmd Cmd) {
+	if !p.canRecoverModel() {
+		p.render(model)
+		return model, nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			m, cmd = p.recoverModel(r)
+			p.render(m)
+		}
+	}()
+	p.render(model)
+	return model, nil
+}
+
+// canRecoverModel reports whether a panicking model can be replaced with the
+// recovery model.
+func (p *Program) canRecoverModel() bool {
+	return p.recoveryModel != nil && !p.recovered && !p.startupOptions.has(withoutCatchPanics)
+}
+
+// recoverModel returns the recovery model for the given panic value and the
+// command returned by its Init. The recovery model is only used once: if it
+// panics too, the program stops.
+func (p *Program) recoverModel(r interface{}) (Model, Cmd) {
+	p.recovered = true
+	m := p.recoveryModel(r)
+	return m, m.Init()
+}
@@ -0,0 +1,83 @@
+package tea
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type recoveryTestModel struct {
+	r       interface{}
+	panicky bool
+}
+
+func (m recoveryTestModel) Init() Cmd { return nil }
+
+func (m recoveryTestModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(panicMsg); ok && m.panicky {
+		panic("recovery model panic")
+	}
+	return m, nil
+}
+
+func (m recoveryTestModel) View() string {
+	return fmt.Sprintf("recovered from %v, press q to quit\n", m.r)
+}
+
+func TestRecoveryModel(t *testing.T) {
+	tests := []struct {
+		name  string
+		model Model
+	}{
+		{name: "update", model: &testModel{}},
+		{name: "view", model: &panicViewModel{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var in bytes.Buffer
+
+			p := NewProgram(test.model, WithInput(&in), WithOutput(&buf), WithRecoveryModel(func(r interface{}) Model {
+				return recoveryTestModel{r: r}
+			}))
+			errc := make(chan error, 1)
+			go func() {
+				_, err := p.Run()
+				errc <- err
+			}()
+
+			p.Send(panicMsg{})
+			// The program stays alive and keeps handling messages.
+			if !p.SendSync(incrementMsg{}) {
+				t.Fatal("expected the program to keep running after the panic")
+			}
+
+			p.Quit()
+			if err := <-errc; err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(buf.String(), "recovered from testing") {
+				t.Fatalf("expected the recovery model's view, got %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestRecoveryModelPanics(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	p := NewProgram(&testModel{}, WithInput(&in), WithOutput(&buf), WithRecoveryModel(func(r interface{}) Model {
+		return recoveryTestModel{r: r, panicky: true}
+	}))
+	go func() {
+		p.Send(panicMsg{})
+		p.Send(panicMsg{})
+	}()
+
+	if _, err := p.Run(); !errors.Is(err, ErrProgramPanic) {
+		t.Fatalf("Expected %v, got %v", ErrProgramPanic, err)
+	}
+}
@@ -0,0 +1,97 @@
+package tea
+
+import (
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// RenderStats describes the work done by the renderer so far. Latencies are
+// the time it took to flush frames to the output, reported as percentiles.
+// They're approximations with a resolution of a power of two, which is enough
+// to detect regressions, for instance in CI, but not to compare small
+// differences.
+type RenderStats struct {
+	// Frames is the number of frames written to the output.
+	Frames uint64
+
+	// FlushP50, FlushP95 and FlushP99 are the 50th, 95th and 99th percentiles
+	// of frame flush durations, or zero if no frame has been flushed yet.
+	FlushP50 time.Duration
+	FlushP95 time.Duration
+	FlushP99 time.Duration
+
+	// Uptime is how long the program has been running, see Program.Uptime.
+	Uptime time.Duration
+}
+
+// RenderStats returns statistics about the frames rendered by the program.
+// They're only kept by the standard renderer; with a nil renderer only the
+// uptime is set.
+func (p *Program) RenderStats() RenderStats {
+	uptime := p.Uptime()
+
+	p.rendererMtx.RLock()
+	defer p.rendererMtx.RUnlock()
+
+	r, ok := p.renderer.(*standardRenderer)
+	if !ok {
+		return RenderStats{Uptime: uptime}
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return RenderStats{
+		Frames:   atomic.LoadUint64(&r.framesRendered),
+		FlushP50: r.flushLatency.percentile(0.5),
+		FlushP95: r.flushLatency.percentile(0.95),
+		FlushP99: r.flushLatency.percentile(0.99),
+		Uptime:   uptime,
+	}
+}
+
+// latencyBuckets is the number of buckets of a latencyHistogram. The last one
+// holds every duration from about 8 seconds on.
+const latencyBuckets = 24
+
+// latencyHistogram counts durations in buckets of powers of two microseconds,
+// keeping its size bounded regardless of the number of durations recorded.
+// Bucket i holds durations below 2^i microseconds.
+type latencyHistogram struct {
+	counts [latencyBuckets]uint64
+	total  uint64
+}
+
+// record adds a duration to the histogram.
+func (h *latencyHistogram) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	i := bits.Len64(uint64(d / time.Microsecond))
+	if i >= latencyBuckets {
+		i = latencyBuckets - 1
+	}
+	h.counts[i]++
+	h.total++
+}
+
+// percentile returns the upper bound of the bucket holding the q-th quantile
+// of the recorded durations, or zero if there are none.
+func (h *latencyHistogram) percentile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(h.total)))
+	if rank == 0 {
+		rank = 1
+	}
+	var seen uint64
+	for i, n := range h.counts {
+		seen += n
+		if seen >= rank {
+			return time.Microsecond << i
+		}
+	}
+	return time.Microsecond << (latencyBuckets - 1)
+}
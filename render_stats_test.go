@@ -0,0 +1,67 @@
+package tea
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	var h latencyHistogram
+	if p := h.percentile(0.5); p != 0 {
+		t.Fatalf("expected no latency without records, got %v", p)
+	}
+
+	for i := 1; i <= 100; i++ {
+		h.record(time.Duration(i) * time.Millisecond)
+	}
+
+	for _, test := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.5, 50 * time.Millisecond},
+		{0.95, 95 * time.Millisecond},
+		{0.99, 99 * time.Millisecond},
+	} {
+		// Percentiles are reported as the upper bound of their bucket, which
+		// is at most twice the actual value.
+		if got := h.percentile(test.q); got < test.want || got > 2*test.want {
+			t.Errorf("expected p%.0f between %v and %v, got %v", test.q*100, test.want, 2*test.want, got)
+		}
+	}
+}
+
+func TestProgramRenderStats(t *testing.T) {
+	r, _ := newStdRendererForTest(t)
+	r.width, r.height = 80, 24
+	p := &Program{renderer: r}
+
+	if stats := p.RenderStats(); stats != (RenderStats{}) {
+		t.Fatalf("expected empty stats before rendering, got %+v", stats)
+	}
+
+	const frames = 200
+	for i := range frames {
+		r.write(strings.Repeat(strings.Repeat("x", i%80)+"\n", i%24) + "frame")
+		r.flush()
+	}
+
+	stats := p.RenderStats()
+	if stats.Frames != frames {
+		t.Fatalf("expected %d frames, got %d", frames, stats.Frames)
+	}
+	if stats.FlushP50 <= 0 || stats.FlushP50 > stats.FlushP95 || stats.FlushP95 > stats.FlushP99 {
+		t.Fatalf("expected ordered percentiles, got %+v", stats)
+	}
+	if stats.FlushP99 > time.Second {
+		t.Fatalf("expected flushes to take well under a second, got %+v", stats)
+	}
+}
+
+func TestProgramRenderStatsWithoutRenderer(t *testing.T) {
+	p := NewProgram(&testModel{}, WithoutRenderer())
+	if stats := p.RenderStats(); stats != (RenderStats{}) {
+		t.Fatalf("expected empty stats, got %+v", stats)
+	}
+}
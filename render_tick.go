@@ -0,0 +1,38 @@
+package tea
+
+import "time"
+
+// RenderTickMsg is delivered to Update at the frame rate with
+// WithContinuousRendering, so that views depending on time keep animating
+// without any other messages. Models that don't animate can ignore it.
+type RenderTickMsg struct {
+	Time time.Time
+}
+
+// handleRenderTicks sends a RenderTickMsg at the frame rate until the program
+// exits. Ticks are skipped while the program is busy rather than queued up.
+func (p *Program) handleRenderTicks() chan struct{} {
+	ch := make(chan struct{})
+
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(time.Second / time.Duration(normalizeFPS(p.fps)))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case t := <-ticker.C:
+				select {
+				case <-p.ctx.Done():
+					return
+				case p.msgs <- RenderTickMsg{Time: t}:
+				}
+			}
+		}
+	}()
+
+	return ch
+}
@@ -0,0 +1,38 @@
+package tea
+
+import "strings"
+
+// RenderView renders the model's view once, the way a running program with the
+// given options would render it to a window of the given size, and returns
+// the result. It doesn't run the program: it doesn't read input, handle
+// signals or start any goroutines. This is handy to test the rendering of a
+// model in isolation.
+//
+// Lines wider than the window are truncated and, if the view is taller than
+// the window, lines are dropped from the top, just like the renderer does.
+// Options affecting the view, such as WithViewTransform, WithLoadingView,
+// WithMaxWidth and WithFullHeightInline, are applied. A width or height of
+// zero means that dimension is unknown, and isn't limited.
+//
+//	got := tea.RenderView(model, 80, 24)
+func RenderView(m Model, width, height int, opts ...ProgramOption) string {
+	p := NewProgram(m, opts...)
+	defer p.cancel()
+
+	view, ok := p.view(m)
+	if !ok {
+		return ""
+	}
+
+	r := &standardRenderer{
+		width:            width,
+		height:           height,
+		maxWidth:         p.maxWidth,
+		fullHeightInline: p.startupOptions.has(withFullHeightInline),
+		statusLine:       p.statusLine,
+	}
+	if r.statusLine != nil {
+		r.status = statusLine(r.statusLine())
+	}
+	return strings.Join(r.truncateLines(r.frameLines(view)), "\n")
+}
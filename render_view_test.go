@@ -0,0 +1,68 @@
+package tea
+
+import (
+	"strings"
+	"testing"
+)
+
+type viewTestModel string
+
+func (m viewTestModel) Init() Cmd               { return nil }
+func (m viewTestModel) Update(Msg) (Model, Cmd) { return m, nil }
+func (m viewTestModel) View() string            { return string(m) }
+
+func TestRenderView(t *testing.T) {
+	m := viewTestModel("0123456789\nab\nwide \x1b[1mbold\x1b[0m text")
+
+	tests := []struct {
+		name          string
+		width, height int
+		opts          []ProgramOption
+		want          string
+	}{
+		{
+			name: "unlimited",
+			want: string(m),
+		},
+		{
+			name:  "truncated",
+			width: 5,
+			want:  "01234\nab\nwide \x1b[1m\x1b[0m",
+		},
+		{
+			name:   "too tall",
+			width:  7,
+			height: 2,
+			want:   "ab\nwide \x1b[1mbo\x1b[0m",
+		},
+		{
+			name:  "transformed",
+			width: 5,
+			opts: []ProgramOption{WithViewTransform(func(view string) string {
+				return "> " + view
+			})},
+			want: "> 012\nab\nwide \x1b[1m\x1b[0m",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := RenderView(m, test.width, test.height, test.opts...); got != test.want {
+				t.Fatalf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRenderViewMatchesRenderer(t *testing.T) {
+	m := viewTestModel("0123456789\nab\nwide \x1b[1mbold\x1b[0m text")
+
+	r, _ := newStdRendererForTest(t)
+	r.smartRepaint = true // keeps the frame as it appeared on screen
+	r.handleMessages(WindowSizeMsg{Width: 7, Height: 10})
+	r.write(m.View())
+	r.flush()
+
+	if got, want := RenderView(m, 7, 10), strings.Join(r.lastFrame, "\n"); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -2,9 +2,15 @@ package tea
 
 import (
 	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/bubbletea/internal/sgr"
 	"github.com/charmbracelet/x/ansi"
 )
 
@@ -86,6 +92,29 @@ func TestStandardRendererQueuedMessagesIgnoredInAltScreen(t *testing.T) {
 	}
 }
 
+func TestProgramPendingPrintLines(t *testing.T) {
+	r, _ := newStdRendererForTest(t)
+	p := &Program{renderer: r}
+
+	r.handleMessages(printLineMessage{messageBody: "one\ntwo"})
+	r.handleMessages(printLineMessage{messageBody: "three"})
+	if got := p.PendingPrintLines(); got != 3 {
+		t.Fatalf("expected 3 pending lines, got %d", got)
+	}
+
+	r.write("frame")
+	r.flush()
+	if got := p.PendingPrintLines(); got != 0 {
+		t.Fatalf("expected no pending lines after a flush, got %d", got)
+	}
+
+	r.handleMessages(printLineMessage{messageBody: "four"})
+	r.enterAltScreen()
+	if got := p.PendingPrintLines(); got != 0 {
+		t.Fatalf("expected no pending lines in the alt screen, got %d", got)
+	}
+}
+
 func TestStandardRendererWindowSizeTriggersRepaint(t *testing.T) {
 	r, out := newStdRendererForTest(t)
 
@@ -117,6 +146,121 @@ func TestStandardRendererWindowSizeTriggersRepaint(t *testing.T) {
 	}
 }
 
+func TestStandardRendererSmartRepaint(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	r.smartRepaint = true
+
+	const content = "short\nlines"
+	r.handleMessages(WindowSizeMsg{Width: 20, Height: 10})
+	r.write(content)
+	r.flush()
+	if !strings.Contains(out.String(), "short") {
+		t.Fatalf("expected initial frame, got %q", out.String())
+	}
+
+	// Resizing to a width that doesn't change the visible output shouldn't
+	// write anything.
+	out.Reset()
+	r.handleMessages(WindowSizeMsg{Width: 40, Height: 10})
+	r.write(content)
+	r.flush()
+	if out.Len() != 0 {
+		t.Fatalf("expected no output for a resize that doesn't change the frame, got %q", out.String())
+	}
+
+	// Resizing to a width that truncates the output repaints.
+	r.handleMessages(WindowSizeMsg{Width: 3, Height: 10})
+	r.write(content)
+	r.flush()
+	got := out.String()
+	if !strings.Contains(got, "sho") || strings.Contains(got, "shor") {
+		t.Fatalf("expected a repaint truncated to the new width, got %q", got)
+	}
+}
+
+func TestStandardRendererMaximizeViewport(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	r.maxWidth = 5
+
+	const content = "0123456789"
+	r.handleMessages(WindowSizeMsg{Width: 10, Height: 5})
+	r.write(content)
+	r.flush()
+	if got := out.String(); !strings.Contains(got, "01234") || strings.Contains(got, "012345") {
+		t.Fatalf("expected frame capped to 5 cells, got %q", got)
+	}
+
+	out.Reset()
+	r.handleMessages(maximizeViewportMsg{})
+	r.write(content)
+	r.flush()
+	if got := out.String(); !strings.Contains(got, content) {
+		t.Fatalf("expected full width frame after maximizing, got %q", got)
+	}
+
+	out.Reset()
+	r.handleMessages(restoreViewportMsg{})
+	r.write(content)
+	r.flush()
+	if got := out.String(); !strings.Contains(got, "01234") || strings.Contains(got, "012345") {
+		t.Fatalf("expected frame capped to 5 cells after restoring, got %q", got)
+	}
+}
+
+func TestStandardRendererAutoResetSGR(t *testing.T) {
+	tests := []struct {
+		name  string
+		view  string
+		reset bool
+	}{
+		{name: "open", view: "\x1b[31mred", reset: true},
+		{name: "closed", view: "\x1b[31mred\x1b[0m"},
+		{name: "closed without params", view: "\x1b[1;31mred\x1b[m"},
+		{name: "unstyled", view: "plain"},
+		{name: "color with zero", view: "\x1b[38;5;0mblack", reset: true},
+		{name: "reset then styled", view: "\x1b[0;1mbold", reset: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, out := newStdRendererForTest(t)
+			r.autoResetSGR = true
+
+			r.write(test.view)
+			r.flush()
+
+			got := strings.TrimSuffix(out.String(), "\r")
+			if reset := strings.HasSuffix(got, test.view+sgr.Reset); reset != test.reset {
+				t.Fatalf("expected reset %t, got %q", test.reset, out.String())
+			}
+		})
+	}
+}
+
+func TestStandardRendererSynchronizedOutput(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	r.synchronizedOutput = true
+
+	for _, frame := range []string{"first", "second"} {
+		out.Reset()
+		if frame == "second" {
+			r.handleMessages(printLineMessage{messageBody: "printed"})
+		}
+		r.write(frame)
+		r.flush()
+
+		got := out.String()
+		if !strings.HasPrefix(got, ansi.SetSynchronizedOutputMode) || !strings.HasSuffix(got, ansi.ResetSynchronizedOutputMode) {
+			t.Fatalf("expected frame to be wrapped in synchronized output sequences, got %q", got)
+		}
+		if !strings.Contains(got, frame) {
+			t.Fatalf("expected frame %q in output, got %q", frame, got)
+		}
+	}
+	if !strings.Contains(out.String(), "printed") {
+		t.Fatalf("expected queued line inside the synchronized update, got %q", out.String())
+	}
+}
+
 func TestStandardRendererAltScreenSequences(t *testing.T) {
 	r, out := newStdRendererForTest(t)
 
@@ -192,6 +336,126 @@ func TestStandardRendererSetIgnoredLinesSkipsRendering(t *testing.T) {
 	}
 }
 
+func TestStandardRendererSetIgnoredLines(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	r.handleMessages(WindowSizeMsg{Width: 80, Height: 24})
+
+	r.write("header\nbody\nfooter")
+	r.flush()
+	r.handleMessages(SetIgnoredLines(0, 1)())
+	// Lines beyond the frame are left alone.
+	r.handleMessages(SetIgnoredLines(5, 10)())
+	if len(r.ignoreLines) != 1 {
+		t.Fatalf("expected only the header to be ignored, got %v", r.ignoreLines)
+	}
+
+	out.Reset()
+	r.write("header-2\nbody-2\nfooter-2")
+	r.flush()
+	got := out.String()
+	if strings.Contains(got, "header-2") {
+		t.Fatalf("ignored line should not be repainted, got %q", got)
+	}
+	if !strings.Contains(got, "body-2") || !strings.Contains(got, "footer-2") {
+		t.Fatalf("other lines should be repainted, got %q", got)
+	}
+
+	r.handleMessages(ClearIgnoredLines())
+	out.Reset()
+	r.write("header-3\nbody-2\nfooter-2")
+	r.flush()
+	if !strings.Contains(out.String(), "header-3") {
+		t.Fatalf("clearing ignored lines should resume rendering, got %q", out.String())
+	}
+}
+
+func TestStandardRendererStatusLine(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	calls := 0
+	r.statusLine = func() string {
+		calls++
+		return fmt.Sprintf("status %d\nignored", calls)
+	}
+	r.handleMessages(WindowSizeMsg{Width: 80, Height: 5})
+
+	// Inline, the status line ends the frame.
+	r.write("a\nb")
+	r.flush()
+	if want := []string{"a", "b", "status 1"}; !reflect.DeepEqual(r.lastRenderedLines, want) {
+		t.Fatalf("expected frame %q, got %q", want, r.lastRenderedLines)
+	}
+
+	// In the alt screen, it's pinned to the last row, even after a resize.
+	r.enterAltScreen()
+	r.write("a\nb")
+	r.flush()
+	if want := []string{"a", "b", "", "", "status 2"}; !reflect.DeepEqual(r.lastRenderedLines, want) {
+		t.Fatalf("expected frame %q, got %q", want, r.lastRenderedLines)
+	}
+	r.handleMessages(WindowSizeMsg{Width: 80, Height: 3})
+	r.write("a\nb\nc")
+	r.flush()
+	if want := []string{"b", "c", "status 3"}; !reflect.DeepEqual(r.lastRenderedLines, want) {
+		t.Fatalf("expected frame %q, got %q", want, r.lastRenderedLines)
+	}
+
+	// A new status is rendered even when the view is the same.
+	out.Reset()
+	r.write("a\nb\nc")
+	r.flush()
+	if got := out.String(); !strings.Contains(got, "status 4") || strings.Contains(got, "ignored") {
+		t.Fatalf("expected the new status line to be rendered, got %q", got)
+	}
+}
+
+func TestStandardRendererOriginMode(t *testing.T) {
+	tests := []struct {
+		name       string
+		originMode *bool
+		msg        Msg
+		expected   string
+	}{
+		{
+			name:     "unmanaged",
+			msg:      ScrollUp([]string{"new"}, 3, 6)(),
+			expected: "\x1b[3;6r\x1b[3;H\x1b[Lnew\x1b[;10r\x1b[H",
+		},
+		{
+			name:       "scroll up in origin mode",
+			originMode: &[]bool{true}[0],
+			msg:        ScrollUp([]string{"new"}, 3, 6)(),
+			expected:   "\x1b[3;6r\x1b[?6h\x1b[1;H\x1b[Lnew\x1b[?6l\x1b[;10r\x1b[H",
+		},
+		{
+			name:       "scroll down in origin mode",
+			originMode: &[]bool{true}[0],
+			msg:        ScrollDown([]string{"new"}, 3, 6)(),
+			expected:   "\x1b[3;6r\x1b[?6h\x1b[4;H\r\nnew\x1b[?6l\x1b[;10r\x1b[H",
+		},
+		{
+			name:       "scroll down without origin mode",
+			originMode: &[]bool{false}[0],
+			msg:        ScrollDown([]string{"new"}, 3, 6)(),
+			expected:   "\x1b[3;6r\x1b[?6l\x1b[6;H\r\nnew\x1b[;10r\x1b[H",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, out := newStdRendererForTest(t)
+			r.originMode = tt.originMode
+			r.handleMessages(WindowSizeMsg{Width: 80, Height: 10})
+			r.enterAltScreen()
+			out.Reset()
+
+			r.handleMessages(tt.msg)
+			if got := out.String(); got != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestStandardRendererSyncScrollArea(t *testing.T) {
 	r, out := newStdRendererForTest(t)
 
@@ -287,3 +551,237 @@ func TestStandardRendererScrollCommands(t *testing.T) {
 		t.Fatalf("scroll down should reset margins, got %q", downOut)
 	}
 }
+
+func TestStandardRendererSetWorkingDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses unix paths")
+	}
+	r, out := newStdRendererForTest(t)
+
+	msg, ok := SetWorkingDirectory("/tmp/my dir/../my files\x07")().(setWorkingDirectoryMsg)
+	if !ok {
+		t.Fatalf("SetWorkingDirectory should produce a setWorkingDirectoryMsg")
+	}
+	r.handleMessages(msg)
+
+	want := "\x1b]7;file://" + msg.host + "/tmp/my%20files\x07"
+	if got := out.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestStandardRendererNotify(t *testing.T) {
+	tests := []struct {
+		name   string
+		format NotificationFormat
+		want   string
+	}{
+		{"osc 9", NotificationOSC9, "\x1b]9;done]2;pwned\x07"},
+		{"osc 777", NotificationOSC777, "\x1b]777;notify;Build, tests;done]2;pwned\x07"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, out := newStdRendererForTest(t)
+			r.notificationFormat = tt.format
+
+			r.handleMessages(Notify("Build; tests", "done\x07\x1b]2;pwned\x07")())
+			if got := out.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStandardRendererSetCursorPosition(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	r.hideCursor()
+
+	r.handleMessages(SetCursorPosition(1, 4)())
+	if !strings.Contains(out.String(), ansi.ShowCursor) {
+		t.Fatalf("expected the cursor to be shown, got %q", out.String())
+	}
+
+	r.write("first\nsecond\nthird")
+	r.flush()
+	frame := out.String()
+	content := strings.Index(frame, "third")
+	placement := strings.LastIndex(frame, ansi.CursorUp(1)+"\r"+ansi.CursorForward(4))
+	if content < 0 || placement < content {
+		t.Fatalf("expected the cursor to be placed after the frame, got %q", frame)
+	}
+
+	// The cursor is moved back before the next frame.
+	out.Reset()
+	r.write("first\nsecond\nfourth")
+	r.flush()
+	if got := out.String(); !strings.HasPrefix(got, ansi.CursorDown(1)+"\r") {
+		t.Fatalf("expected the cursor to be moved back first, got %q", got)
+	}
+}
+
+func TestStandardRendererUpdatesLineInPlace(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+
+	r.write("downloading 100%")
+	r.flush()
+	out.Reset()
+
+	// A view without a trailing newline leaves the cursor on its last line,
+	// so updates overwrite it, erasing what's left of the longer line.
+	r.write("done")
+	r.flush()
+	if got, want := out.String(), "done"+ansi.EraseLineRight+"\r"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if r.linesRendered != 1 {
+		t.Fatalf("expected 1 line rendered, got %d", r.linesRendered)
+	}
+}
+
+func TestStandardRendererFreezeUI(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	r.ticker = time.NewTicker(time.Hour)
+	defer r.ticker.Stop()
+
+	r.write("first")
+	r.flush()
+
+	r.handleMessages(FreezeUI())
+	out.Reset()
+	for _, view := range []string{"second", "third"} {
+		r.write(view)
+		r.flush()
+	}
+	if frames := atomic.LoadUint64(&r.framesRendered); frames != 1 || out.Len() > 0 {
+		t.Fatalf("expected no frames while frozen, got %d frames and %q", frames, out.String())
+	}
+
+	r.handleMessages(ThawUI())
+	if frames := atomic.LoadUint64(&r.framesRendered); frames != 2 {
+		t.Fatalf("expected one frame on thaw, got %d frames", frames-1)
+	}
+	if got := out.String(); !strings.Contains(got, "third") || strings.Contains(got, "second") {
+		t.Fatalf("expected the latest view to be rendered, got %q", got)
+	}
+}
+
+func TestStandardRendererSoftWrap(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	r.softWrap = true
+	r.handleMessages(WindowSizeMsg{Width: 4, Height: 10})
+
+	r.write("ab\x1b[1mcdef\x1b[0m\n\u4f60\u597d\u4e16\u754c\nxy")
+	r.flush()
+
+	expected := "\rab\x1b[1mcd\r\nef\x1b[0m" + ansi.EraseLineRight + "\r\n" +
+		"\u4f60\u597d\r\n\u4e16\u754c\r\n" +
+		"xy" + ansi.EraseLineRight + "\r"
+	if got := out.String(); got != expected {
+		t.Fatalf("expected the long lines to wrap\ngot:  %q\nwant: %q", got, expected)
+	}
+	if r.linesRendered != 5 {
+		t.Fatalf("expected 5 rows to be rendered, got %d", r.linesRendered)
+	}
+}
+
+func TestStandardRendererFlushPrintQueue(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+
+	r.write("line 1\nline 2")
+	r.flush()
+
+	r.handleMessages(Println("log a")())
+	r.handleMessages(Println("log b")())
+	var frameLog bytes.Buffer
+	r.frameLog = &frameLog
+	out.Reset()
+	r.handleMessages(FlushPrintQueue())
+
+	expected := ansi.CursorUp(1) +
+		"log a\r\n" +
+		"log b\r\n" +
+		"\rline 1\r\n" +
+		"line 2\r"
+	if got := out.String(); got != expected {
+		t.Fatalf("expected the queued lines above the last frame\ngot:  %q\nwant: %q", got, expected)
+	}
+	// Repainting the frame counts as a frame, like any other.
+	if frames := atomic.LoadUint64(&r.framesRendered); frames != 2 {
+		t.Fatalf("expected the repaint to be counted as a frame, got %d frames", frames)
+	}
+	if !strings.HasPrefix(frameLog.String(), "frame 1 at ") {
+		t.Fatalf("expected the repaint in the frame log, got %q", frameLog.String())
+	}
+
+	// The view hasn't changed, so there's nothing left to render.
+	out.Reset()
+	r.write("line 1\nline 2")
+	r.flush()
+	if out.Len() > 0 {
+		t.Fatalf("expected nothing to be written, got %q", out.String())
+	}
+}
+
+func TestStandardRendererRawWrite(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+
+	const seq = "\x1b]1337;SetUserVar=foo=YmFy\x07"
+	r.handleMessages(RawWrite(seq)())
+	if got := out.String(); got != seq {
+		t.Fatalf("got %q, want %q", got, seq)
+	}
+
+	if cmd := RawWrite("line one\nline two"); cmd != nil {
+		t.Fatalf("expected sequences with newlines to be rejected")
+	}
+}
+
+func TestStandardRendererRegion(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	r.region = &region{x: 4, y: 2, width: 5, height: 3}
+
+	r.write("0123456789\nab")
+	r.flush()
+
+	expected := "\x1b[3;5H01234" + "\x1b[4;5Hab   " + "\x1b[5;5H     "
+	if got := out.String(); got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+
+	// Only the lines that changed are painted again.
+	out.Reset()
+	r.write("0123456789\nabc")
+	r.flush()
+	if got, expected := out.String(), "\x1b[4;5Habc  "; got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestProgramRepaint(t *testing.T) {
+	r, out := newStdRendererForTest(t)
+	p := &Program{renderer: r}
+
+	r.write("frame")
+	r.flush()
+	out.Reset()
+
+	// Identical content isn't written again...
+	r.write("frame")
+	r.flush()
+	if out.Len() != 0 {
+		t.Fatalf("expected no output for an identical frame, got %q", out.String())
+	}
+
+	// ...unless a repaint was requested.
+	p.Repaint()
+	r.write("frame")
+	r.flush()
+	if !strings.Contains(out.String(), "frame") {
+		t.Fatalf("expected the frame to be repainted, got %q", out.String())
+	}
+}
+
+func TestProgramRepaintWithoutRenderer(t *testing.T) {
+	p := NewProgram(nil, WithoutRenderer())
+	p.Repaint()
+}
@@ -0,0 +1,72 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || aix || zos
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris aix zos
+
+package tea
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+func TestFullHeightInlinePadsFrameToWindowHeight(t *testing.T) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Fatalf("pty.Open() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = master.Close()
+		_ = slave.Close()
+	})
+	go func() { _, _ = io.Copy(io.Discard, master) }()
+
+	setSize := func(width, height int) {
+		ws := &pty.Winsize{Cols: uint16(width), Rows: uint16(height)}
+		if err := pty.Setsize(master, ws); err != nil {
+			t.Fatalf("pty.Setsize() failed: %v", err)
+		}
+	}
+	setSize(40, 10)
+
+	p := NewProgram(&testModel{}, WithInput(nil), WithOutput(slave), WithFullHeightInline(), WithoutSignalHandler())
+	r, _ := newRenderer(slave, false, defaultFPS).(*standardRenderer)
+	p.renderer = r
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	waitForRenderedLines := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			r.mtx.Lock()
+			got, alt := len(r.lastRenderedLines), r.altScreenActive
+			r.mtx.Unlock()
+			if alt {
+				t.Fatalf("full height inline mode should not enter the alt screen")
+			}
+			if got == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("frame was never padded to %d lines", want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	waitForRenderedLines(10)
+
+	setSize(40, 16)
+	p.Send(WindowSize()())
+	waitForRenderedLines(16)
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package tea
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReplayProgram plays back a session recorded with [WithFrameLog], writing
+// each frame to its output at the pace it was originally rendered. It has no
+// model and reads no input, which makes it handy for embedding demos.
+type ReplayProgram struct {
+	r    io.Reader
+	out  io.Writer
+	quit chan struct{}
+	once sync.Once
+}
+
+// NewReplayProgram creates a program replaying the frame log read from r to
+// out.
+func NewReplayProgram(r io.Reader, out io.Writer) *ReplayProgram {
+	return &ReplayProgram{
+		r:    r,
+		out:  out,
+		quit: make(chan struct{}),
+	}
+}
+
+// Run plays back the recording, blocking until the last frame has been
+// written or the replay is stopped with Quit. It returns an error if the
+// recording can't be read or parsed, or the output can't be written.
+func (p *ReplayProgram) Run() error {
+	return p.RunContext(context.Background())
+}
+
+// RunContext is like Run, but also stops when ctx is done, in which case it
+// returns an error wrapping ErrProgramKilled and the context's error.
+func (p *ReplayProgram) RunContext(ctx context.Context) error {
+	var start, first time.Time
+	s := bufio.NewScanner(p.r)
+	s.Buffer(nil, 1<<24) //nolint:mnd
+	for {
+		f, err := readReplayFrame(s)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if start.IsZero() {
+			start, first = time.Now(), f.at
+		}
+		if d := f.at.Sub(first) - time.Since(start); d > 0 {
+			t := time.NewTimer(d)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return fmt.Errorf("%w: %w", ErrProgramKilled, ctx.Err())
+			case <-p.quit:
+				t.Stop()
+				return nil
+			case <-t.C:
+			}
+		}
+
+		if _, err := io.WriteString(p.out, f.data); err != nil {
+			return fmt.Errorf("error writing frame %d: %w", f.n, err)
+		}
+	}
+}
+
+// Quit stops the replay. It's safe to call more than once.
+func (p *ReplayProgram) Quit() {
+	p.once.Do(func() { close(p.quit) })
+}
+
+// replayFrame is a frame of a frame log.
+type replayFrame struct {
+	n    int
+	at   time.Time
+	data string
+}
+
+// readReplayFrame reads the next frame from a frame log, which has the form:
+//
+//	frame 0 at 2006-01-02T15:04:05.999999999Z07:00
+//	"quoted frame"
+//
+// followed by an empty line. It returns io.EOF once there are no more frames.
+func readReplayFrame(s *bufio.Scanner) (replayFrame, error) {
+	var header string
+	for header == "" {
+		if !s.Scan() {
+			if err := s.Err(); err != nil {
+				return replayFrame{}, fmt.Errorf("error reading recording: %w", err)
+			}
+			return replayFrame{}, io.EOF
+		}
+		header = s.Text()
+	}
+
+	var f replayFrame
+	n, at, ok := strings.Cut(strings.TrimPrefix(header, "frame "), " at ")
+	if !ok || !strings.HasPrefix(header, "frame ") {
+		return f, fmt.Errorf("invalid frame header %q", header)
+	}
+	var err error
+	if f.n, err = strconv.Atoi(n); err != nil {
+		return f, fmt.Errorf("invalid frame number in %q: %w", header, err)
+	}
+	if f.at, err = time.Parse(time.RFC3339Nano, at); err != nil {
+		return f, fmt.Errorf("invalid frame time in %q: %w", header, err)
+	}
+
+	if !s.Scan() {
+		return f, fmt.Errorf("missing data for frame %d", f.n)
+	}
+	if f.data, err = strconv.Unquote(s.Text()); err != nil {
+		return f, fmt.Errorf("invalid data for frame %d: %w", f.n, err)
+	}
+	return f, nil
+}
@@ -0,0 +1,112 @@
+package tea
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// timedWriter records when each write was made.
+type timedWriter struct {
+	mtx    sync.Mutex
+	writes []string
+	times  []time.Time
+}
+
+func (w *timedWriter) Write(b []byte) (int, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.writes = append(w.writes, string(b))
+	w.times = append(w.times, time.Now())
+	return len(b), nil
+}
+
+func recording(frames ...string) string {
+	var b strings.Builder
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, f := range frames {
+		at := start.Add(time.Duration(i) * 50 * time.Millisecond)
+		fmt.Fprintf(&b, "frame %d at %s\n%s\n\n", i, at.Format(time.RFC3339Nano), strconv.Quote(f))
+	}
+	return b.String()
+}
+
+func TestReplayProgram(t *testing.T) {
+	out := &timedWriter{}
+	p := NewReplayProgram(strings.NewReader(recording("one\r\n", "\x1b[Atwo", "three")), out)
+	start := time.Now()
+	if err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"one\r\n", "\x1b[Atwo", "three"}
+	if len(out.writes) != len(want) {
+		t.Fatalf("expected frames %q, got %q", want, out.writes)
+	}
+	for i, w := range want {
+		if out.writes[i] != w {
+			t.Fatalf("expected frames %q, got %q", want, out.writes)
+		}
+		// Frames were recorded 50ms apart.
+		at := out.times[i].Sub(start)
+		lo := time.Duration(i) * 50 * time.Millisecond
+		if hi := lo + 40*time.Millisecond; at < lo || at > hi {
+			t.Errorf("expected frame %d between %s and %s, got %s", i, lo, hi, at)
+		}
+	}
+}
+
+func TestReplayProgramQuit(t *testing.T) {
+	var out bytes.Buffer
+	p := NewReplayProgram(strings.NewReader(recording("one", "two")), &out)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p.Quit()
+	}()
+	if err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "one" {
+		t.Fatalf("expected the replay to stop after the first frame, got %q", out.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p = NewReplayProgram(strings.NewReader(recording("one", "two")), &out)
+	if err := p.RunContext(ctx); !errors.Is(err, ErrProgramKilled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the replay to be killed, got %v", err)
+	}
+}
+
+func TestReplayProgramInvalidRecording(t *testing.T) {
+	var out bytes.Buffer
+	p := NewReplayProgram(strings.NewReader("frame x at now\n\"\"\n"), &out)
+	if err := p.Run(); err == nil {
+		t.Fatal("expected an error for an invalid recording")
+	}
+}
+
+func TestReplayFrameLog(t *testing.T) {
+	// A session recorded with WithFrameLog replays to the frames rendered.
+	var log, rendered bytes.Buffer
+	var in bytes.Buffer
+	p := NewProgram(&testModel{}, WithInput(&in), WithOutput(&rendered), WithFrameLog(&log))
+	go p.Send(sequenceMsg{func() Msg { return WindowSizeMsg{Width: 80, Height: 24} }, Quit})
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var replayed bytes.Buffer
+	if err := NewReplayProgram(&log, &replayed).Run(); err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Len() == 0 || !strings.Contains(rendered.String(), replayed.String()) {
+		t.Fatalf("expected the replay %q to match the rendered frames %q", replayed.String(), rendered.String())
+	}
+}
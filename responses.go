@@ -0,0 +1,102 @@
+package tea
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// terminalResponse is implemented by the messages the input reader reports
+// for responses to terminal queries.
+type terminalResponse interface {
+	// responseTo returns the capability the response answers a query for.
+	responseTo() capability
+
+	// sequence returns the response as the terminal sent it, re-encoded from
+	// its parsed form.
+	sequence() string
+}
+
+func (m cursorPositionMsg) responseTo() capability { return capabilityCursorPosition }
+func (m cursorPositionMsg) sequence() string {
+	return fmt.Sprintf("\x1b[%d;%dR", m.row, m.col)
+}
+
+func (m backgroundColorMsg) responseTo() capability { return capabilityBackgroundColor }
+func (m backgroundColorMsg) sequence() string {
+	if m.color == nil {
+		return "\x1b]11;\x1b\\"
+	}
+	r, g, b, _ := m.color.RGBA()
+	return fmt.Sprintf("\x1b]11;rgb:%04x/%04x/%04x\x1b\\", r, g, b)
+}
+
+func (m keyboardFlagsMsg) responseTo() capability { return capabilityKeyboardFlags }
+func (m keyboardFlagsMsg) sequence() string {
+	return fmt.Sprintf("\x1b[?%du", int(m))
+}
+
+func (m terminalVersionMsg) responseTo() capability { return capabilityTerminalVersion }
+func (m terminalVersionMsg) sequence() string {
+	if m.version == "" {
+		return "\x1bP>|" + m.name + "\x1b\\"
+	}
+	return "\x1bP>|" + m.name + " " + m.version + "\x1b\\"
+}
+
+func (m modeReportMsg) responseTo() capability {
+	if m.mode == int(ansi.SynchronizedOutputMode) {
+		return capabilitySynchronizedOutput
+	}
+	return 0
+}
+
+func (m modeReportMsg) sequence() string {
+	return fmt.Sprintf("\x1b[?%d;%d$y", m.mode, m.value)
+}
+
+// pendingResponse is a response the program expects for a query it wrote.
+type pendingResponse struct {
+	to       capability
+	deadline time.Time
+}
+
+// expectResponses records that the program wrote queries for the given
+// capabilities, so their responses are routed to the program rather than
+// reported as unmatched. Queries are given up on after the same timeout as
+// TerminalInfo. It must be called from the event loop.
+func (p *Program) expectResponses(c capability) {
+	deadline := time.Now().Add(terminalInfoTimeout)
+	for bit := capability(1); bit <= c; bit <<= 1 {
+		if c&bit != 0 {
+			p.pendingResponses = append(p.pendingResponses, pendingResponse{to: bit, deadline: deadline})
+		}
+	}
+}
+
+// matchResponse matches a response to the oldest outstanding query it
+// answers and reports whether there was one. Unmatched responses are passed to
+// the hook set with WithUnmatchedResponseHook, if any. It must be called from
+// the event loop.
+func (p *Program) matchResponse(r terminalResponse) bool {
+	now := time.Now()
+	matched := false
+	pending := p.pendingResponses[:0]
+	for _, q := range p.pendingResponses {
+		switch {
+		case now.After(q.deadline):
+			// Given up on.
+		case !matched && q.to == r.responseTo():
+			matched = true
+		default:
+			pending = append(pending, q)
+		}
+	}
+	p.pendingResponses = pending
+
+	if !matched && p.unmatchedResponseHook != nil {
+		p.unmatchedResponseHook(r.sequence())
+	}
+	return matched
+}
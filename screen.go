@@ -9,6 +9,48 @@ type WindowSizeMsg struct {
 	Height int
 }
 
+// WindowPixelSizeMsg reports the size of the terminal in pixels. It's sent
+// right after a WindowSizeMsg, if the terminal reports its size in pixels,
+// which not all terminals do.
+type WindowPixelSizeMsg struct {
+	// Width and Height are the size of the terminal in cells, as in the
+	// WindowSizeMsg sent before.
+	Width  int
+	Height int
+
+	// PixelWidth and PixelHeight are the size of the terminal in pixels.
+	PixelWidth  int
+	PixelHeight int
+}
+
+// CellPixelRatio returns the size of a cell in pixels, which is useful to
+// scale images rendered with sixels or the Kitty graphics protocol. It
+// returns zeros if the size is unknown.
+func (m WindowPixelSizeMsg) CellPixelRatio() (width, height float64) {
+	if m.Width <= 0 || m.Height <= 0 || m.PixelWidth <= 0 || m.PixelHeight <= 0 {
+		return 0, 0
+	}
+	return float64(m.PixelWidth) / float64(m.Width), float64(m.PixelHeight) / float64(m.Height)
+}
+
+// InitialWindowSizeMsg reports the terminal size when the program starts. It's
+// sent once, right before the first WindowSizeMsg, so models can tell their
+// first layout apart from later resizes, for instance to do one-time setup.
+// Models that don't need to can ignore it and handle WindowSizeMsg only.
+type InitialWindowSizeMsg struct {
+	Width  int
+	Height int
+}
+
+// TooSmallMsg is sent instead of a WindowSizeMsg when the terminal is smaller
+// than the minimum size set with WithMinimumSize. Models can use it to render
+// a placeholder asking to enlarge the terminal. Once the terminal is large
+// enough again, a regular WindowSizeMsg is sent.
+type TooSmallMsg struct {
+	Width  int
+	Height int
+}
+
 // ClearScreen is a special command that tells the program to clear the screen
 // before the next update. This can be used to move the cursor to the top left
 // of the screen and clear visual clutter when the alt screen is not in use.
@@ -52,6 +94,31 @@ func ExitAltScreen() Msg {
 // alternate screen buffer. You can send a exitAltScreenMsg with ExitAltScreen.
 type exitAltScreenMsg struct{}
 
+// MaximizeViewport is a special command that tells the Bubble Tea program to
+// render frames at the full width of the terminal, ignoring the maximum width
+// set with WithMaxWidth. This is useful for a "zen mode" toggle. Use
+// RestoreViewport to return to the maximum width.
+func MaximizeViewport() Msg {
+	return maximizeViewportMsg{}
+}
+
+// maximizeViewportMsg is an internal message that signals the renderer to
+// ignore the maximum width. You can send a maximizeViewportMsg with
+// MaximizeViewport.
+type maximizeViewportMsg struct{}
+
+// RestoreViewport is a special command that tells the Bubble Tea program to
+// render frames at the maximum width set with WithMaxWidth again after
+// MaximizeViewport.
+func RestoreViewport() Msg {
+	return restoreViewportMsg{}
+}
+
+// restoreViewportMsg is an internal message that signals the renderer to
+// apply the maximum width again. You can send a restoreViewportMsg with
+// RestoreViewport.
+type restoreViewportMsg struct{}
+
 // EnableMouseCellMotion is a special command that enables mouse click,
 // release, and wheel events. Mouse movement events are also captured if
 // a mouse button is pressed (i.e., drag events).
@@ -116,6 +183,78 @@ func ShowCursor() Msg {
 // this message with ShowCursor.
 type showCursorMsg struct{}
 
+// EnableEcho is a special command that tells the terminal to echo the keys
+// typed, like it does outside of Bubble Tea. Input is still delivered to
+// Update as usual. This is handy for a simple, one-line prompt.
+//
+// Echo is restored to its original state when the program quits. It's not
+// supported on Windows.
+func EnableEcho() Msg {
+	return enableEchoMsg{}
+}
+
+// enableEchoMsg is an internal message that signals to enable input echo.
+// You can send an enableEchoMsg with EnableEcho.
+type enableEchoMsg struct{}
+
+// DisableEcho is a special command that stops the terminal from echoing the
+// keys typed after EnableEcho, which is the default. Use it, for example, when
+// switching to a password prompt.
+func DisableEcho() Msg {
+	return disableEchoMsg{}
+}
+
+// disableEchoMsg is an internal message that signals to disable input echo.
+// You can send a disableEchoMsg with DisableEcho.
+type disableEchoMsg struct{}
+
+// EnableLineWrap is a special command that tells the terminal to wrap lines
+// longer than the width of the window, which is the default.
+func EnableLineWrap() Msg {
+	return enableLineWrapMsg{}
+}
+
+// enableLineWrapMsg is an internal message that signals to enable line
+// wrapping. You can send an enableLineWrapMsg with EnableLineWrap.
+type enableLineWrapMsg struct{}
+
+// DisableLineWrap is a special command that tells the terminal to clip lines
+// longer than the width of the window instead of wrapping them, which can
+// prevent wide content, such as tables, from corrupting the layout.
+//
+// Note that line wrapping will be automatically enabled when the program
+// quits.
+func DisableLineWrap() Msg {
+	return disableLineWrapMsg{}
+}
+
+// disableLineWrapMsg is an internal message that signals to disable line
+// wrapping. You can send a disableLineWrapMsg with DisableLineWrap.
+type disableLineWrapMsg struct{}
+
+// FreezeUI is a special command that stops rendering frames until ThawUI,
+// so an expensive operation can run without any redraws. Unlike
+// ReleaseTerminal, the terminal is left as it is, and the program keeps
+// processing messages. Views are still produced, but only the latest one is
+// rendered once the UI thaws. Quitting renders the final frame regardless.
+func FreezeUI() Msg {
+	return freezeUIMsg{}
+}
+
+// freezeUIMsg is an internal message that signals to stop rendering. You
+// can send a freezeUIMsg with FreezeUI.
+type freezeUIMsg struct{}
+
+// ThawUI is a special command that resumes rendering after FreezeUI,
+// rendering the latest view right away.
+func ThawUI() Msg {
+	return thawUIMsg{}
+}
+
+// thawUIMsg is an internal message that signals to resume rendering. You can
+// send a thawUIMsg with ThawUI.
+type thawUIMsg struct{}
+
 // EnableBracketedPaste is a special command that tells the Bubble Tea program
 // to accept bracketed paste input.
 //
@@ -236,13 +375,18 @@ func (p *Program) DisableMouseAllMotion() {
 	}
 }
 
-// SetWindowTitle sets the terminal window title.
-//
-// Deprecated: Use the SetWindowTitle command instead.
+// SetWindowTitle sets the terminal window title from outside the program, for
+// instance from another goroutine. From Update, return the SetWindowTitle
+// command instead; calling this method there would block forever. If the
+// program hasn't started yet, the title is set on startup.
 func (p *Program) SetWindowTitle(title string) {
-	if p.renderer != nil {
-		p.renderer.setWindowTitle(title)
-	} else {
+	p.rendererMtx.RLock()
+	started := p.renderer != nil
+	p.rendererMtx.RUnlock()
+
+	if !started {
 		p.startupTitle = title
+		return
 	}
+	p.Send(setWindowTitleMsg(title))
 }
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestClearMsg(t *testing.T) {
@@ -52,6 +54,16 @@ func TestClearMsg(t *testing.T) {
 			cmds:     []Cmd{HideCursor, ShowCursor},
 			expected: "\x1b[?25l\x1b[?2004h\x1b[?25l\x1b[?25h\rsuccess\x1b[K\r\n\x1b[K\r\x1b[2K\r\x1b[?2004l\x1b[?25h\x1b[?1002l\x1b[?1003l\x1b[?1006l",
 		},
+		{
+			name:     "line_wrap_disable",
+			cmds:     []Cmd{DisableLineWrap},
+			expected: "\x1b[?25l\x1b[?2004h\x1b[?7l\rsuccess\x1b[K\r\n\x1b[K\r\x1b[2K\r\x1b[?7h\x1b[?2004l\x1b[?25h\x1b[?1002l\x1b[?1003l\x1b[?1006l",
+		},
+		{
+			name:     "line_wrap_disable_enable",
+			cmds:     []Cmd{DisableLineWrap, EnableLineWrap},
+			expected: "\x1b[?25l\x1b[?2004h\x1b[?7l\x1b[?7h\rsuccess\x1b[K\r\n\x1b[K\r\x1b[2K\r\x1b[?2004l\x1b[?25h\x1b[?1002l\x1b[?1003l\x1b[?1006l",
+		},
 		{
 			name:     "bp_stop_start",
 			cmds:     []Cmd{DisableBracketedPaste, EnableBracketedPaste},
@@ -67,7 +79,7 @@ func TestClearMsg(t *testing.T) {
 			m := &testModel{}
 			p := NewProgram(m, WithInput(&in), WithOutput(&buf))
 
-			test.cmds = append([]Cmd{func() Msg { return WindowSizeMsg{80, 24} }}, test.cmds...)
+			test.cmds = append([]Cmd{func() Msg { return WindowSizeMsg{Width: 80, Height: 24} }}, test.cmds...)
 			test.cmds = append(test.cmds, Quit)
 			go p.Send(test.cmds)
 
@@ -90,7 +102,7 @@ func runProgramForScreenTest(t *testing.T, opts []ProgramOption, cmds sequenceMs
 	allOpts := append([]ProgramOption{WithInput(&in), WithOutput(&buf)}, opts...)
 	p := NewProgram(&testModel{}, allOpts...)
 
-	sequence := append(sequenceMsg{func() Msg { return WindowSizeMsg{80, 24} }}, cmds...)
+	sequence := append(sequenceMsg{func() Msg { return WindowSizeMsg{Width: 80, Height: 24} }}, cmds...)
 	sequence = append(sequence, Quit)
 
 	go p.Send(sequence)
@@ -102,6 +114,28 @@ func runProgramForScreenTest(t *testing.T, opts []ProgramOption, cmds sequenceMs
 	return buf.String()
 }
 
+func TestKeepOutputOnExit(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf), WithKeepOutputOnExit())
+	go p.Send(sequenceMsg{func() Msg { return WindowSizeMsg{Width: 80, Height: 24} }, Quit})
+
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	i := strings.LastIndex(out, "success")
+	if i == -1 {
+		t.Fatalf("expected the final frame in output, got %q", out)
+	}
+	if strings.Contains(out[i:], ansi.EraseEntireLine) {
+		t.Fatalf("expected the final frame not to be erased, got %q", out)
+	}
+}
+
 func TestReportFocusCommands(t *testing.T) {
 	output := runProgramForScreenTest(t, nil, sequenceMsg{EnableReportFocus, DisableReportFocus})
 
@@ -132,9 +166,9 @@ func TestWithReportFocusOption(t *testing.T) {
 
 func TestMouseStartupOptions(t *testing.T) {
 	tests := []struct {
-		name     string
-		opts     []ProgramOption
-		enable   []string
+		name   string
+		opts   []ProgramOption
+		enable []string
 	}{
 		{
 			name:   "cell_motion_option",
@@ -159,3 +193,39 @@ func TestMouseStartupOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestWithAltScreenOption(t *testing.T) {
+	output := runProgramForScreenTest(t, []ProgramOption{WithAltScreen()}, nil)
+
+	enter := strings.Index(output, ansi.SetAltScreenSaveCursorMode)
+	if enter == -1 || enter > strings.Index(output, "success") {
+		t.Fatalf("expected the alt screen to be entered before the first frame, got %q", output)
+	}
+	if !strings.HasSuffix(output, ansi.ResetAltScreenSaveCursorMode+ansi.ShowCursor) {
+		t.Fatalf("expected the alt screen to be exited on teardown, got %q", output)
+	}
+}
+
+func TestProgramSetWindowTitle(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+	waitForModelExecution(t, m)
+
+	p.SetWindowTitle("my\x07 title\x1b]0;evil")
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	if want := ansi.SetWindowTitle("my title]0;evil"); !strings.Contains(buf.String(), want) {
+		t.Fatalf("expected the sanitized title sequence %q in output, got %q", want, buf.String())
+	}
+}
@@ -0,0 +1,144 @@
+package tea
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RunScript runs the program, driving it with the input script read from r
+// instead of the program's input. This is useful to reproduce bug reports.
+//
+// The script consists of one directive per line. Empty lines and lines
+// starting with # are ignored:
+//
+//	# Type "hi", then press enter.
+//	key: runes:hi
+//	key: enter
+//	# Raw input, with Go escape sequences.
+//	raw: \x1b[A
+//	sleep: 100ms
+//	key: ctrl+c
+//
+// Keys use the format of [Key.MarshalText]. A line without a directive is
+// read as a key. Each key is processed by the program before the next line
+// runs.
+//
+// It returns the final model and error like [Program.Run]. If the script
+// can't be parsed, the program isn't run.
+func RunScript(p *Program, r io.Reader) (Model, error) {
+	steps, err := parseScript(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the script drives the program.
+	WithInput(nil)(p)
+
+	go func() {
+		for _, step := range steps {
+			if !step(p) {
+				return
+			}
+		}
+	}()
+
+	return p.Run()
+}
+
+// scriptStep is a step of an input script. It reports whether the program is
+// still running.
+type scriptStep func(p *Program) bool
+
+// parseScript parses an input script, see RunScript.
+func parseScript(r io.Reader) ([]scriptStep, error) {
+	var steps []scriptStep
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		directive, arg, ok := strings.Cut(line, ":")
+		switch directive = strings.TrimSpace(directive); {
+		case ok && directive == "key":
+			line = strings.TrimSpace(arg)
+		case ok && directive == "sleep":
+			d, err := time.ParseDuration(strings.TrimSpace(arg))
+			if err != nil {
+				return nil, fmt.Errorf("bubbletea: script line %d: %w", n, err)
+			}
+			steps = append(steps, sleepStep(d))
+			continue
+		case ok && directive == "raw":
+			msgs, err := parseRawInput(strings.TrimSpace(arg))
+			if err != nil {
+				return nil, fmt.Errorf("bubbletea: script line %d: %w", n, err)
+			}
+			steps = append(steps, sendStep(msgs...))
+			continue
+		}
+
+		var k Key
+		if err := k.UnmarshalText([]byte(line)); err != nil {
+			return nil, fmt.Errorf("bubbletea: script line %d: %w", n, err)
+		}
+		steps = append(steps, sendStep(KeyMsg(k)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("bubbletea: error reading script: %w", err)
+	}
+	return steps, nil
+}
+
+// parseRawInput parses escaped raw input into the messages it produces.
+func parseRawInput(s string) ([]Msg, error) {
+	raw, err := strconv.Unquote(`"` + strings.ReplaceAll(s, `"`, `\"`) + `"`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw input %q: %w", s, err)
+	}
+
+	var msgs []Msg
+	for b := []byte(raw); len(b) > 0; {
+		w, msg := detectOneMsg(b, false)
+		if w == 0 {
+			return nil, fmt.Errorf("incomplete raw input %q", s)
+		}
+		b = b[w:]
+		if msg != nil {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs, nil
+}
+
+// sendStep sends the given messages to the program, one after another.
+func sendStep(msgs ...Msg) scriptStep {
+	return func(p *Program) bool {
+		for _, msg := range msgs {
+			if !p.SendSync(msg) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// sleepStep pauses the script.
+func sleepStep(d time.Duration) scriptStep {
+	return func(p *Program) bool {
+		t := time.NewTimer(d)
+		defer t.Stop()
+
+		select {
+		case <-p.ctx.Done():
+			return false
+		case <-t.C:
+			return true
+		}
+	}
+}
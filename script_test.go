@@ -0,0 +1,59 @@
+package tea
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type scriptTestModel struct {
+	keys []string
+}
+
+func (m *scriptTestModel) Init() Cmd { return nil }
+
+func (m *scriptTestModel) Update(msg Msg) (Model, Cmd) {
+	if k, ok := msg.(KeyMsg); ok {
+		m.keys = append(m.keys, k.String())
+		if k.Type == KeyCtrlC {
+			return m, Quit
+		}
+	}
+	return m, nil
+}
+
+func (m *scriptTestModel) View() string { return "" }
+
+func TestRunScript(t *testing.T) {
+	script := `
+# Type, move up, then quit.
+key: runes:hi
+enter
+raw: \x1b[A
+sleep: 10ms
+key: ctrl+c
+`
+	var buf bytes.Buffer
+	m := &scriptTestModel{}
+	if _, err := RunScript(NewProgram(m, WithOutput(&buf)), strings.NewReader(script)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"hi", "enter", "up", "ctrl+c"}
+	if !reflect.DeepEqual(m.keys, expected) {
+		t.Fatalf("expected keys %v, got %v", expected, m.keys)
+	}
+}
+
+func TestRunScriptInvalid(t *testing.T) {
+	for _, script := range []string{"key: nope", "sleep: soon", `raw: \x1bP>|`} {
+		t.Run(script, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := NewProgram(&scriptTestModel{}, WithOutput(&buf))
+			if _, err := RunScript(p, strings.NewReader(script)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
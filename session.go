@@ -0,0 +1,167 @@
+package tea
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// Session runs Programs one after another on the same terminal, such as the
+// sub-apps of a launcher. Rather than every Program putting the terminal into
+// raw mode and the alternate screen buffer and taking it out again, which
+// flickers between programs, the session keeps the terminal set up from the
+// first Program until Close is called.
+//
+//	s := tea.NewSession()
+//	defer s.Close()
+//
+//	if _, err := s.Run(tea.NewProgram(menu{}, tea.WithAltScreen())); err != nil {
+//	    return err
+//	}
+//	if _, err := s.Run(tea.NewProgram(app{}, tea.WithAltScreen())); err != nil {
+//	    return err
+//	}
+//
+// Programs in a session must be run one at a time.
+type Session struct {
+	mtx sync.Mutex
+
+	// ttyInput is the terminal held in raw mode and rawState its state
+	// before entering raw mode. ownsInput is set if the session took over the
+	// file from a program that opened it, and has to close it.
+	ttyInput  term.File
+	rawState  *term.State
+	ownsInput bool
+
+	// altScreen is set while the alternate screen buffer, entered by a
+	// program, is kept active on output.
+	altScreen bool
+	output    io.Writer
+}
+
+// NewSession returns a new session.
+func NewSession() *Session {
+	return &Session{}
+}
+
+// Run runs p as part of the session, like Program.Run. If p doesn't use the
+// alternate screen buffer while the previous program did, the alternate
+// screen is exited first.
+func (s *Session) Run(p *Program) (Model, error) {
+	s.mtx.Lock()
+	if s.altScreen && !p.startupOptions.has(withAltScreen) {
+		s.exitAltScreen()
+	}
+	s.mtx.Unlock()
+
+	p.session = s
+	return p.Run()
+}
+
+// Close exits the alternate screen buffer and restores the terminal to the
+// state it had before the session's first program put it into raw mode.
+func (s *Session) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.altScreen {
+		s.exitAltScreen()
+	}
+	var err error
+	if s.rawState != nil {
+		state := s.rawState
+		s.rawState = nil
+		if rerr := term.Restore(s.ttyInput.Fd(), state); rerr != nil {
+			err = fmt.Errorf("error restoring console: %w", rerr)
+		}
+	}
+	s.releaseInput()
+	return err
+}
+
+// releaseInput forgets about the terminal the session holds, closing it if
+// the session owns it.
+func (s *Session) releaseInput() {
+	if s.ownsInput {
+		_ = s.ttyInput.Close()
+	}
+	s.ttyInput, s.ownsInput = nil, false
+}
+
+// exitAltScreen exits the alternate screen buffer kept by the session.
+func (s *Session) exitAltScreen() {
+	s.altScreen = false
+	_, _ = io.WriteString(s.output, ansi.ResetAltScreenSaveCursorMode+ansi.ShowCursor)
+}
+
+// takeRawState hands the state of the terminal before raw mode over to a
+// program using the same terminal, which is already in raw mode then.
+func (s *Session) takeRawState(f term.File) *term.State {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.rawState == nil || !sameFile(s.ttyInput, f) {
+		return nil
+	}
+	state := s.rawState
+	s.rawState = nil
+	s.releaseInput()
+	return state
+}
+
+// own takes over closing f, the input a program opened itself, if the
+// session holds on to it. It reports whether it did.
+func (s *Session) own(f term.File) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.rawState == nil || s.ttyInput != f {
+		return false
+	}
+	s.ownsInput = true
+	return true
+}
+
+// sameFile reports whether a and b refer to the same terminal, even if they
+// were opened separately, such as two programs each opening /dev/tty.
+func sameFile(a, b term.File) bool {
+	if a.Fd() == b.Fd() {
+		return true
+	}
+	fa, aok := a.(*os.File)
+	fb, bok := b.(*os.File)
+	if !aok || !bok {
+		return false
+	}
+	sa, err := fa.Stat()
+	if err != nil {
+		return false
+	}
+	sb, err := fb.Stat()
+	return err == nil && os.SameFile(sa, sb)
+}
+
+// keep takes over the raw mode and the alternate screen buffer of a program
+// that is shutting down, so they are left as they are for the next program.
+func (s *Session) keep(p *Program) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	p.rawModeMtx.Lock()
+	if p.ttyInput != nil && p.previousTtyInputState != nil && s.rawState == nil {
+		s.ttyInput = p.ttyInput
+		s.rawState = p.previousTtyInputState
+		p.previousTtyInputState = nil
+	}
+	p.rawModeMtx.Unlock()
+
+	if p.renderer != nil && p.renderer.altScreen() {
+		s.altScreen = true
+		s.output = p.output
+		p.keepAltScreen = true
+	}
+}
@@ -0,0 +1,33 @@
+package tea
+
+// queueShellCommandMsg is an internal message that sets the command to hand
+// off to the shell once the program exits.
+type queueShellCommandMsg string
+
+// QueueShellCommand produces a command that hands s off to the user's shell
+// once the program quits, for instance to let a launcher leave a command ready
+// at the prompt. Queuing another command replaces the previous one, and
+// queuing an empty string cancels it.
+//
+// Typing into the terminal's input buffer with TIOCSTI is disabled on modern
+// systems, and there's no escape sequence that writes to a shell's line
+// editor. Instead, after the terminal has been restored, the command is
+// printed to stdout on its own line and a small shell integration picks it up.
+// For that to work the program has to render somewhere else, such as stderr:
+//
+//	p := tea.NewProgram(model{}, tea.WithOutput(os.Stderr))
+//
+// The command is then placed at the prompt with, in zsh:
+//
+//	print -z "$(launcher)"
+//
+// or in bash, with a key binding:
+//
+//	bind -x '"\C-o": READLINE_LINE="$(launcher)"; READLINE_POINT=${#READLINE_LINE}'
+//
+// Nothing is printed if the program is killed.
+func QueueShellCommand(s string) Cmd {
+	return func() Msg {
+		return queueShellCommandMsg(s)
+	}
+}
@@ -0,0 +1,54 @@
+package tea
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestQueueShellCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmds     sequenceMsg
+		expected string
+	}{
+		{
+			name:     "queued",
+			cmds:     sequenceMsg{QueueShellCommand("ls -la")},
+			expected: "ls -la\n",
+		},
+		{
+			name:     "replaced",
+			cmds:     sequenceMsg{QueueShellCommand("ls"), QueueShellCommand("git status")},
+			expected: "git status\n",
+		},
+		{
+			name:     "cancelled",
+			cmds:     sequenceMsg{QueueShellCommand("ls"), QueueShellCommand("")},
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close() //nolint:errcheck
+
+			stdout := os.Stdout
+			os.Stdout = f
+			defer func() { os.Stdout = stdout }()
+			runProgramForScreenTest(t, nil, test.cmds)
+
+			got, err := os.ReadFile(f.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.expected {
+				t.Fatalf("expected %q on stdout, got %q", test.expected, got)
+			}
+		})
+	}
+}
@@ -36,3 +36,24 @@ func (p *Program) listenForResize(done chan struct{}) {
 		p.checkResize()
 	}
 }
+
+// listenForFatalSignals restores the terminal when the process receives
+// SIGQUIT, which would otherwise terminate it without Bubble Tea getting the
+// chance to clean up. The signal is then raised again so that the process
+// terminates as it normally would.
+//
+// SIGHUP is left alone, as applications commonly handle it themselves, for
+// instance to reload their configuration.
+func (p *Program) listenForFatalSignals() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGQUIT)
+	defer signal.Stop(sig)
+
+	select {
+	case <-p.ctx.Done():
+	case s := <-sig:
+		signal.Stop(sig)
+		restoreTerminals()
+		_ = syscall.Kill(syscall.Getpid(), s.(syscall.Signal))
+	}
+}
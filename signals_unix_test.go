@@ -5,6 +5,7 @@ package tea
 
 import (
 	"context"
+	"io"
 	"os"
 	"sync/atomic"
 	"syscall"
@@ -71,6 +72,10 @@ func waitForWindowSizeMsg(t *testing.T, msgs <-chan Msg, timeout time.Duration)
 
 	select {
 	case msg := <-msgs:
+		if _, ok := msg.(InitialWindowSizeMsg); ok {
+			// Sent right before the initial WindowSizeMsg.
+			return waitForWindowSizeMsg(t, msgs, timeout)
+		}
 		ws, ok := msg.(WindowSizeMsg)
 		if !ok {
 			t.Fatalf("expected WindowSizeMsg, got %T", msg)
@@ -129,6 +134,109 @@ func TestHandleResizeEmitsInitialWindowSize(t *testing.T) {
 	}
 }
 
+func TestWithoutResizeHandler(t *testing.T) {
+	h := newResizeTestHarness(t)
+	defer h.close()
+	h.program.startupOptions |= withoutResizeHandler
+
+	h.setSize(80, 24)
+	done := h.program.handleResize()
+	waitForHandler(t, done)
+	defer h.program.cancel()
+
+	// The initial size is still reported.
+	msg := waitForWindowSizeMsg(t, h.program.msgs, time.Second)
+	if msg.Width != 80 || msg.Height != 24 {
+		t.Fatalf("initial window size = (%d, %d), want (80, 24)", msg.Width, msg.Height)
+	}
+
+	h.setSize(100, 30)
+	sendSigwinch(t)
+	expectNoWindowSizeMsg(t, h.program.msgs, 100*time.Millisecond)
+
+	h.program.SetSize(120, 40)
+	msg = waitForWindowSizeMsg(t, h.program.msgs, time.Second)
+	if msg.Width != 120 || msg.Height != 40 {
+		t.Fatalf("window size = (%d, %d), want (120, 40)", msg.Width, msg.Height)
+	}
+}
+
+func TestHandleResizeReportsCellPixelRatio(t *testing.T) {
+	h := newResizeTestHarness(t)
+	defer h.close()
+
+	ws := &pty.Winsize{Cols: 80, Rows: 24, X: 800, Y: 480}
+	if err := pty.Setsize(h.master, ws); err != nil {
+		t.Fatalf("pty.Setsize() failed: %v", err)
+	}
+	done := h.program.handleResize()
+	defer func() {
+		h.program.cancel()
+		waitForHandler(t, done)
+	}()
+
+	if msg := waitForWindowSizeMsg(t, h.program.msgs, time.Second); msg.Width != 80 || msg.Height != 24 {
+		t.Fatalf("window size = (%d, %d), want (80, 24)", msg.Width, msg.Height)
+	}
+	var msg WindowPixelSizeMsg
+	select {
+	case m := <-h.program.msgs:
+		var ok bool
+		if msg, ok = m.(WindowPixelSizeMsg); !ok {
+			t.Fatalf("expected WindowPixelSizeMsg, got %T", m)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for WindowPixelSizeMsg")
+	}
+	if msg.PixelWidth != 800 || msg.PixelHeight != 480 {
+		t.Fatalf("pixel size = (%d, %d), want (800, 480)", msg.PixelWidth, msg.PixelHeight)
+	}
+	if cw, ch := msg.CellPixelRatio(); cw != 10 || ch != 20 {
+		t.Fatalf("cell pixel ratio = (%v, %v), want (10, 20)", cw, ch)
+	}
+
+	// Without the size in pixels, only the size in cells is sent.
+	h.setSize(80, 24)
+	h.program.checkResize()
+	waitForWindowSizeMsg(t, h.program.msgs, time.Second)
+	expectNoWindowSizeMsg(t, h.program.msgs, 100*time.Millisecond)
+}
+
+func TestHandleResizeEmitsInitialWindowSizeMsg(t *testing.T) {
+	h := newResizeTestHarness(t)
+	defer h.close()
+
+	h.setSize(80, 24)
+	done := h.program.handleResize()
+	defer func() {
+		h.program.cancel()
+		waitForHandler(t, done)
+	}()
+
+	select {
+	case msg := <-h.program.msgs:
+		initial, ok := msg.(InitialWindowSizeMsg)
+		if !ok {
+			t.Fatalf("expected InitialWindowSizeMsg, got %T", msg)
+		}
+		if initial.Width != 80 || initial.Height != 24 {
+			t.Fatalf("initial window size = (%d, %d), want (80, 24)", initial.Width, initial.Height)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for InitialWindowSizeMsg")
+	}
+	_ = waitForWindowSizeMsg(t, h.program.msgs, time.Second)
+
+	// Later resizes only report a WindowSizeMsg.
+	h.setSize(100, 30)
+	h.program.checkResize()
+
+	msg := waitForWindowSizeMsg(t, h.program.msgs, time.Second)
+	if msg.Width != 100 || msg.Height != 30 {
+		t.Fatalf("resize message = (%d, %d), want (100, 30)", msg.Width, msg.Height)
+	}
+}
+
 func TestListenForResizePropagatesSizeChanges(t *testing.T) {
 	h := newResizeTestHarness(t)
 	defer h.close()
@@ -203,3 +311,106 @@ func TestListenForResizeHonorsIgnoreSignals(t *testing.T) {
 		t.Fatalf("resumed window size = (%d, %d), want (144, 50)", msg.Width, msg.Height)
 	}
 }
+
+type minimumSizeTestModel struct {
+	sizes chan Msg
+}
+
+func (m minimumSizeTestModel) Init() Cmd { return nil }
+
+func (m minimumSizeTestModel) Update(msg Msg) (Model, Cmd) {
+	switch msg.(type) {
+	case WindowSizeMsg, TooSmallMsg:
+		m.sizes <- msg
+	}
+	return m, nil
+}
+
+func (m minimumSizeTestModel) View() string { return "" }
+
+func TestMinimumSize(t *testing.T) {
+	h := newResizeTestHarness(t)
+	defer h.close()
+
+	go func() { _, _ = io.Copy(io.Discard, h.master) }()
+
+	m := minimumSizeTestModel{sizes: make(chan Msg, 8)}
+	h.setSize(80, 24)
+	p := NewProgram(m, WithInput(nil), WithOutput(h.slave), WithMinimumSize(40, 10), WithoutSignalHandler())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	expect := func(want Msg) {
+		t.Helper()
+		select {
+		case got := <-m.sizes:
+			if got != want {
+				t.Fatalf("expected %#v, got %#v", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %#v", want)
+		}
+	}
+
+	expect(WindowSizeMsg{Width: 80, Height: 24})
+
+	h.setSize(30, 24)
+	p.Send(WindowSize()())
+	expect(TooSmallMsg{Width: 30, Height: 24})
+
+	h.setSize(80, 5)
+	p.Send(WindowSize()())
+	expect(TooSmallMsg{Width: 80, Height: 5})
+
+	h.setSize(40, 10)
+	p.Send(WindowSize()())
+	expect(WindowSizeMsg{Width: 40, Height: 10})
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAltScreenEmitsWindowSize(t *testing.T) {
+	h := newResizeTestHarness(t)
+	defer h.close()
+
+	go func() { _, _ = io.Copy(io.Discard, h.master) }()
+
+	m := minimumSizeTestModel{sizes: make(chan Msg, 8)}
+	h.setSize(80, 24)
+	p := NewProgram(m, WithInput(nil), WithOutput(h.slave), WithoutSignalHandler())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	want := WindowSizeMsg{Width: 80, Height: 24}
+	if got := waitForWindowSizeMsg(t, m.sizes, time.Second); got != want {
+		t.Fatalf("initial window size = %#v, want %#v", got, want)
+	}
+
+	p.Send(EnterAltScreen())
+	if got := waitForWindowSizeMsg(t, m.sizes, time.Second); got != want {
+		t.Fatalf("window size after entering the alt screen = %#v, want %#v", got, want)
+	}
+
+	// Entering it again doesn't change anything.
+	p.Send(EnterAltScreen())
+	expectNoWindowSizeMsg(t, m.sizes, 100*time.Millisecond)
+
+	p.Send(ExitAltScreen())
+	if got := waitForWindowSizeMsg(t, m.sizes, time.Second); got != want {
+		t.Fatalf("window size after exiting the alt screen = %#v, want %#v", got, want)
+	}
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -8,3 +8,7 @@ package tea
 func (p *Program) listenForResize(done chan struct{}) {
 	close(done)
 }
+
+// listenForFatalSignals is not available on windows, which lacks the signals
+// it listens for.
+func (p *Program) listenForFatalSignals() {}
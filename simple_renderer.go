@@ -0,0 +1,68 @@
+package tea
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// simpleRenderer is a fallback renderer that prints every new view below the
+// previous one, as a plain command line tool would. It never moves the cursor
+// nor writes any other escape sequence, which makes it suitable for terminals
+// the standard renderer doesn't play well with and for debugging rendering
+// issues. Use WithSimpleRenderer to enable it.
+type simpleRenderer struct {
+	mtx      sync.Mutex
+	out      io.Writer
+	lastView string
+}
+
+func newSimpleRenderer(out io.Writer) *simpleRenderer {
+	return &simpleRenderer{out: out}
+}
+
+// write prints the view if it differs from the last one.
+func (r *simpleRenderer) write(view string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if view == "" || view == r.lastView {
+		return
+	}
+	r.lastView = view
+
+	// Output is written in raw mode, so newlines don't return the carriage.
+	_, _ = io.WriteString(r.out, strings.ReplaceAll(view, "\n", "\r\n")+"\r\n")
+}
+
+// repaint prints the next view even if it didn't change.
+func (r *simpleRenderer) repaint() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.lastView = ""
+}
+
+func (r *simpleRenderer) clearScreen() { r.repaint() }
+
+func (r *simpleRenderer) start()                     {}
+func (r *simpleRenderer) stop()                      {}
+func (r *simpleRenderer) kill()                      {}
+func (r *simpleRenderer) altScreen() bool            { return false }
+func (r *simpleRenderer) enterAltScreen()            {}
+func (r *simpleRenderer) exitAltScreen()             {}
+func (r *simpleRenderer) showCursor()                {}
+func (r *simpleRenderer) hideCursor()                {}
+func (r *simpleRenderer) enableMouseCellMotion()     {}
+func (r *simpleRenderer) disableMouseCellMotion()    {}
+func (r *simpleRenderer) enableMouseAllMotion()      {}
+func (r *simpleRenderer) disableMouseAllMotion()     {}
+func (r *simpleRenderer) enableBracketedPaste()      {}
+func (r *simpleRenderer) disableBracketedPaste()     {}
+func (r *simpleRenderer) enableMouseSGRMode()        {}
+func (r *simpleRenderer) disableMouseSGRMode()       {}
+func (r *simpleRenderer) bracketedPasteActive() bool { return false }
+func (r *simpleRenderer) setWindowTitle(_ string)    {}
+func (r *simpleRenderer) reportFocus() bool          { return false }
+func (r *simpleRenderer) enableReportFocus()         {}
+func (r *simpleRenderer) disableReportFocus()        {}
+func (r *simpleRenderer) resetLinesRendered()        {}
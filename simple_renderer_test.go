@@ -0,0 +1,42 @@
+package tea
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSimpleRendererAppendsViews(t *testing.T) {
+	var out bytes.Buffer
+	r := newSimpleRenderer(&out)
+
+	r.write("count: 1\nstatus")
+	r.write("count: 1\nstatus")
+	r.write("count: 2\nstatus")
+	r.repaint()
+	r.write("count: 2\nstatus")
+
+	want := "count: 1\r\nstatus\r\ncount: 2\r\nstatus\r\ncount: 2\r\nstatus\r\n"
+	if got := out.String(); got != want {
+		t.Fatalf("expected views to be appended:\n%q\ngot:\n%q", want, got)
+	}
+}
+
+func TestWithSimpleRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+	in.Write([]byte("q"))
+
+	p := NewProgram(&testModel{}, WithInput(&in), WithOutput(&buf), WithSimpleRenderer(), WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "success\r\n") {
+		t.Fatalf("expected the view in output, got %q", out)
+	}
+	if strings.Contains(out, "\x1b") {
+		t.Fatalf("expected no escape sequences, got %q", out)
+	}
+}
@@ -0,0 +1,25 @@
+package tea
+
+import "time"
+
+// SlowOutputMsg is sent when writing to the output takes longer than the
+// threshold set with [WithSlowOutputThreshold], which happens when the
+// output is a slow pipe or connection that can't keep up with the frames
+// being rendered. It's sent once per slow write, as soon as the threshold
+// passes, but like any message it only reaches Update once the event loop is
+// free. Writes made while handling a message, such as printing a line, block
+// the event loop, so the message then arrives after the write completes. The
+// program can react by rendering less, for instance by dropping animations.
+type SlowOutputMsg struct{}
+
+// watchWrite calls write, calling onSlowOutput if it takes longer than the
+// slow output threshold.
+func (r *standardRenderer) watchWrite(write func()) {
+	if r.slowOutputThreshold <= 0 || r.onSlowOutput == nil {
+		write()
+		return
+	}
+	t := time.AfterFunc(r.slowOutputThreshold, r.onSlowOutput)
+	defer t.Stop()
+	write()
+}
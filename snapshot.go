@@ -0,0 +1,84 @@
+package tea
+
+// defaultSnapshotDepth is the default number of snapshots a program keeps.
+const defaultSnapshotDepth = 64
+
+// Snapshottable can be implemented by a [Model] to support app-wide undo with
+// [PushSnapshot] and [PopSnapshot].
+type Snapshottable interface {
+	// Snapshot serializes the model's current state.
+	Snapshot() []byte
+
+	// Restore returns the model with a state previously returned by
+	// Snapshot. The returned model replaces the current one, just like the
+	// model returned by Update.
+	Restore([]byte) (Model, error)
+}
+
+// SnapshotRestoreErrorMsg is sent to Update when restoring a snapshot with
+// [PopSnapshot] fails.
+type SnapshotRestoreErrorMsg struct {
+	Err error
+}
+
+// pushSnapshotMsg is an internal message that signals the program to take a
+// snapshot of the model. You can send a pushSnapshotMsg with PushSnapshot.
+type pushSnapshotMsg struct{}
+
+// popSnapshotMsg is an internal message that signals the program to restore
+// the most recent snapshot. You can send a popSnapshotMsg with PopSnapshot.
+type popSnapshotMsg struct{}
+
+// PushSnapshot is a special command that takes a snapshot of the model and
+// pushes it onto the program's snapshot stack. It's a no-op if the model
+// doesn't implement [Snapshottable].
+//
+// The stack holds a limited number of snapshots, see [WithSnapshotDepth].
+// Once it's full, the oldest snapshot is discarded.
+func PushSnapshot() Msg {
+	return pushSnapshotMsg{}
+}
+
+// PopSnapshot is a special command that restores the model to the most
+// recent snapshot taken with [PushSnapshot] and removes it from the stack.
+// It's a no-op if the stack is empty.
+//
+// If restoring fails a [SnapshotRestoreErrorMsg] is sent to Update.
+func PopSnapshot() Msg {
+	return popSnapshotMsg{}
+}
+
+// pushSnapshot takes a snapshot of the model, if it supports it.
+func (p *Program) pushSnapshot(model Model) {
+	s, ok := model.(Snapshottable)
+	if !ok {
+		return
+	}
+
+	depth := p.snapshotDepth
+	if depth < 1 {
+		depth = defaultSnapshotDepth
+	}
+	if len(p.snapshots) >= depth {
+		p.snapshots = p.snapshots[len(p.snapshots)-depth+1:]
+	}
+	p.snapshots = append(p.snapshots, s.Snapshot())
+}
+
+// popSnapshot restores the model to the most recent snapshot, if any, and
+// returns the resulting model.
+func (p *Program) popSnapshot(model Model) Model {
+	s, ok := model.(Snapshottable)
+	if !ok || len(p.snapshots) == 0 {
+		return model
+	}
+
+	data := p.snapshots[len(p.snapshots)-1]
+	p.snapshots = p.snapshots[:len(p.snapshots)-1]
+	restored, err := s.Restore(data)
+	if err != nil {
+		go p.Send(SnapshotRestoreErrorMsg{Err: err})
+		return model
+	}
+	return restored
+}
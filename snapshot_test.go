@@ -0,0 +1,106 @@
+package tea
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type snapshotTestModel struct {
+	counter    int
+	cmds       []Cmd
+	restoreErr error
+	gotErr     error
+}
+
+func (m snapshotTestModel) Init() Cmd {
+	return Sequence(m.cmds...)
+}
+
+func (m snapshotTestModel) Update(msg Msg) (Model, Cmd) {
+	switch msg := msg.(type) {
+	case incrementMsg:
+		m.counter++
+	case SnapshotRestoreErrorMsg:
+		m.gotErr = msg.Err
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m snapshotTestModel) View() string {
+	return strconv.Itoa(m.counter)
+}
+
+func (m snapshotTestModel) Snapshot() []byte {
+	return []byte(strconv.Itoa(m.counter))
+}
+
+func (m snapshotTestModel) Restore(b []byte) (Model, error) {
+	if m.restoreErr != nil {
+		return m, m.restoreErr
+	}
+	n, err := strconv.Atoi(string(b))
+	if err != nil {
+		return m, err
+	}
+	m.counter = n
+	return m, nil
+}
+
+func TestSnapshots(t *testing.T) {
+	increment := func() Msg { return incrementMsg{} }
+
+	run := func(t *testing.T, m snapshotTestModel, opts ...ProgramOption) snapshotTestModel {
+		t.Helper()
+		var buf, in bytes.Buffer
+		opts = append(opts, WithInput(&in), WithOutput(&buf))
+		if m.restoreErr == nil {
+			m.cmds = append(m.cmds, Quit)
+		}
+		final, err := NewProgram(m, opts...).Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return final.(snapshotTestModel)
+	}
+
+	t.Run("undo", func(t *testing.T) {
+		m := snapshotTestModel{cmds: []Cmd{
+			increment, PushSnapshot, increment, increment, PopSnapshot,
+		}}
+		m = run(t, m)
+		if m.counter != 1 {
+			t.Fatalf("expected counter to revert to 1, got %d", m.counter)
+		}
+	})
+
+	t.Run("empty stack", func(t *testing.T) {
+		m := snapshotTestModel{cmds: []Cmd{increment, PopSnapshot}}
+		m = run(t, m)
+		if m.counter != 1 {
+			t.Fatalf("expected popping an empty stack to be a no-op, got %d", m.counter)
+		}
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		m := snapshotTestModel{cmds: []Cmd{
+			PushSnapshot, increment, PushSnapshot, increment, PushSnapshot, increment,
+			PopSnapshot, PopSnapshot, PopSnapshot,
+		}}
+		m = run(t, m, WithSnapshotDepth(2))
+		if m.counter != 1 {
+			t.Fatalf("expected the oldest snapshot to be discarded, got %d", m.counter)
+		}
+	})
+
+	t.Run("restore error", func(t *testing.T) {
+		errRestore := errors.New("restore failed")
+		m := snapshotTestModel{restoreErr: errRestore, cmds: []Cmd{PushSnapshot, PopSnapshot}}
+		m = run(t, m)
+		if !errors.Is(m.gotErr, errRestore) {
+			t.Fatalf("expected a SnapshotRestoreErrorMsg, got %v", m.gotErr)
+		}
+	})
+}
@@ -1,13 +1,20 @@
 package tea
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
 
+	"github.com/charmbracelet/bubbletea/internal/sgr"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/muesli/ansi/compressor"
 )
@@ -56,18 +63,117 @@ type standardRenderer struct {
 	width  int
 	height int
 
+	// if set, an escaped copy of every frame is written here
+	frameLog io.Writer
+
+	// whether to leave the final frame on the screen when stopping
+	keepOutput bool
+
+	// whether frames are wrapped in synchronized output sequences
+	synchronizedOutput bool
+
+	// maximum width of rendered frames, if any, and whether the viewport is
+	// maximized, ignoring that maximum
+	maxWidth  int
+	maximized bool
+
 	// lines explicitly set not to render
 	ignoreLines map[int]struct{}
+
+	// whether frames rendered outside of the alt screen should be padded to
+	// the height of the window
+	fullHeightInline bool
+
+	// number of frames written to the output
+	framesRendered uint64
+
+	// durations of frame flushes, see Program.RenderStats
+	flushLatency latencyHistogram
+
+	// whether to skip repainting after a resize if the frame looks the same
+	// at the new size; resized is set when a resize is pending and lastFrame
+	// holds the last frame as it appeared on screen
+	smartRepaint bool
+	resized      bool
+	lastFrame    []string
+
+	// whether to wrap lines wider than the render width rather than
+	// truncating them
+	softWrap bool
+
+	// whether line wrapping was disabled with DisableLineWrap
+	lineWrapDisabled bool
+
+	// the palette entries changed with SetPaletteColor, by index, so they
+	// can be set again when the terminal is restored after being released
+	palette map[int]setPaletteColorMsg
+
+	// whether to reset styles left open by a frame
+	autoResetSGR bool
+
+	// whether to strip colors from frames and printed lines, see noColor
+	noColor bool
+
+	// region of the terminal frames are drawn into, if any, instead of the
+	// lines at the cursor
+	region *region
+
+	// statusLine returns the line pinned to the bottom of every frame, see
+	// WithStatusLine; status is the line as of the last flush
+	statusLine func() string
+	status     string
+
+	// called with errors writing to the output
+	onWriteError func(error)
+
+	// frozen is set while no frames are rendered, see FreezeUI
+	frozen bool
+
+	// where to put the cursor after a frame, if cursorPlaced is set, see
+	// SetCursorPosition. cursorReturn moves it back to where the frame ends.
+	cursorPlaced bool
+	cursorRow    int
+	cursorCol    int
+	cursorReturn string
+
+	// called when a write takes longer than slowOutputThreshold, see
+	// WithSlowOutputThreshold
+	slowOutputThreshold time.Duration
+	onSlowOutput        func()
+
+	// buffer for the output, flushed after each frame, see WithOutputBuffer;
+	// stopped is set once the renderer no longer flushes it
+	outputBuffer *bufio.Writer
+	stopped      bool
+
+	// whether to set or reset origin mode (DECOM) while painting into a
+	// scroll area, see WithOriginMode; nil leaves it as it is
+	originMode *bool
+
+	// the escape sequence used for desktop notifications, see
+	// WithNotificationFormat
+	notificationFormat NotificationFormat
+}
+
+// region is a rectangular area of the terminal. Its origin is zero-based.
+type region struct {
+	x, y          int
+	width, height int
+}
+
+// normalizeFPS returns the default FPS if fps isn't set, capped to the
+// maximum FPS.
+func normalizeFPS(fps int) int {
+	if fps < 1 {
+		return defaultFPS
+	}
+	return min(fps, maxFPS)
 }
 
 // newRenderer creates a new renderer. Normally you'll want to initialize it
 // with os.Stdout as the first argument.
 func newRenderer(out io.Writer, useANSICompressor bool, fps int) renderer {
-	if fps < 1 {
-		fps = defaultFPS
-	} else if fps > maxFPS {
-		fps = maxFPS
-	}
+	fps = normalizeFPS(fps)
 	r := &standardRenderer{
 		out:                out,
 		mtx:                &sync.Mutex{},
@@ -96,6 +202,15 @@ func (r *standardRenderer) start() {
 	// the done channel and its corresponding sync.Once.
 	r.once = sync.Once{}
 
+	// Buffer the output again, if it was stopped by ReleaseTerminal, and
+	// change the palette back.
+	r.mtx.Lock()
+	r.stopped = false
+	for _, i := range slices.Sorted(maps.Keys(r.palette)) {
+		r.execute(r.palette[i].sequence())
+	}
+	r.mtx.Unlock()
+
 	go r.listen()
 }
 
@@ -106,15 +221,39 @@ func (r *standardRenderer) stop() {
 		r.done <- struct{}{}
 	})
 
+	// Render the final frame even while frozen.
+	r.mtx.Lock()
+	r.frozen = false
+	r.mtx.Unlock()
+
 	// flush locks the mutex
 	r.flush()
 
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 
-	r.execute(ansi.EraseEntireLine)
-	// Move the cursor back to the beginning of the line
-	r.execute("\r")
+	switch {
+	case r.region != nil:
+		// The rest of the terminal belongs to someone else; leave it, and
+		// the frame, alone.
+	case r.keepOutput && !r.altScreenActive:
+		// Leave the final frame on the screen and move the cursor below it.
+		r.execute("\r\n")
+	default:
+		r.execute(ansi.EraseEntireLine)
+		// Move the cursor back to the beginning of the line
+		r.execute("\r")
+	}
+
+	if r.lineWrapDisabled {
+		r.execute(ansi.SetAutoWrapMode)
+		r.lineWrapDisabled = false
+	}
+	r.resetPalette()
+
+	// Nothing flushes the output buffer anymore.
+	r.stopped = true
+	r.flushOutput()
 
 	if r.useANSICompressor {
 		if w, ok := r.out.(io.WriteCloser); ok {
@@ -125,7 +264,44 @@ func (r *standardRenderer) stop() {
 
 // execute writes a sequence to the terminal.
 func (r *standardRenderer) execute(seq string) {
-	_, _ = io.WriteString(r.out, seq)
+	r.writeOut([]byte(seq))
+}
+
+// writeOut writes b to the output, reporting errors to onWriteError. With an
+// output buffer, b is only written once the buffer is flushed, unless the
+// renderer is stopped.
+func (r *standardRenderer) writeOut(b []byte) {
+	// Move the cursor back from the position set with SetCursorPosition
+	// first, as everything else expects it where the last frame left it.
+	if r.cursorReturn != "" {
+		b = append([]byte(r.cursorReturn), b...)
+		r.cursorReturn = ""
+	}
+	if r.outputBuffer == nil {
+		r.watchWrite(func() {
+			r.reportWriteError(r.out.Write(b))
+		})
+		return
+	}
+	r.reportWriteError(r.outputBuffer.Write(b))
+	if r.stopped {
+		r.flushOutput()
+	}
+}
+
+// flushOutput writes the output buffered with WithOutputBuffer, if any.
+func (r *standardRenderer) flushOutput() {
+	if r.outputBuffer != nil && r.outputBuffer.Buffered() > 0 {
+		r.watchWrite(func() {
+			r.reportWriteError(0, r.outputBuffer.Flush())
+		})
+	}
+}
+
+func (r *standardRenderer) reportWriteError(_ int, err error) {
+	if err != nil && r.onWriteError != nil {
+		r.onWriteError(err)
+	}
 }
 
 // kill halts the renderer. The final frame will not be rendered.
@@ -141,6 +317,10 @@ func (r *standardRenderer) kill() {
 	r.execute(ansi.EraseEntireLine)
 	// Move the cursor back to the beginning of the line
 	r.execute("\r")
+	r.resetPalette()
+
+	r.stopped = true
+	r.flushOutput()
 }
 
 // listen waits for ticks on the ticker, or a signal to stop the renderer.
@@ -161,15 +341,54 @@ func (r *standardRenderer) listen() {
 func (r *standardRenderer) flush() {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
+	// Write the frame, and whatever was written since the last one, at once.
+	defer r.flushOutput()
+
+	r.render()
+}
 
-	if r.buf.Len() == 0 || r.buf.String() == r.lastRender {
+// render paints the buffer as a new frame, along with any lines queued for
+// printing. The caller must hold the mutex.
+func (r *standardRenderer) render() {
+	if r.buf.Len() == 0 || r.frozen {
+		// Nothing to do.
+		return
+	}
+	status := r.status
+	if r.statusLine != nil && r.region == nil {
+		status = statusLine(r.statusLine())
+	}
+	if len(r.queuedMessageLines) > 0 && !r.altScreenActive && r.region == nil {
+		// Printing lines above the frame pushes it down, so it has to be
+		// repainted in full.
+		r.repaint()
+	}
+	if r.buf.String() == r.lastRender && status == r.status && !r.resized {
 		// Nothing to do.
 		return
 	}
+	r.status = status
+	start := time.Now()
 
 	// Output buffer.
 	buf := &bytes.Buffer{}
 
+	// Begin a synchronized update so supporting terminals render the frame
+	// atomically.
+	if r.synchronizedOutput {
+		buf.WriteString(ansi.SetSynchronizedOutputMode)
+	}
+
+	if r.region != nil {
+		if r.resized {
+			r.resized = false
+			r.repaint()
+		}
+		newLines := r.paintRegion(buf, strings.Split(r.buf.String(), "\n"))
+		r.finishFlush(buf, newLines, start)
+		return
+	}
+
 	// Moving to the beginning of the section, that we rendered.
 	if r.altScreenActive {
 		buf.WriteString(ansi.CursorHomePosition)
@@ -177,36 +396,25 @@ func (r *standardRenderer) flush() {
 		buf.WriteString(ansi.CursorUp(r.linesRendered - 1))
 	}
 
-	newLines := strings.Split(r.buf.String(), "\n")
+	newLines := r.frameLines(r.buf.String())
 
-	// If we know the output's height, we can use it to determine how many
-	// lines we can render. We drop lines from the top of the render buffer if
-	// necessary, as we can't navigate the cursor into the terminal's scrollback
-	// buffer.
-	if r.height > 0 && len(newLines) > r.height {
-		newLines = newLines[len(newLines)-r.height:]
+	// With smart repaints we only repaint after a resize if the frame
+	// actually looks different at the new size.
+	if r.resized {
+		r.resized = false
+		if len(r.queuedMessageLines) == 0 && slices.Equal(r.truncateLines(newLines), r.lastFrame) {
+			r.lastRender = r.buf.String()
+			r.lastRenderedLines = newLines
+			r.buf.Reset()
+			return
+		}
+		r.repaint()
 	}
 
 	flushQueuedMessages := len(r.queuedMessageLines) > 0 && !r.altScreenActive
 
 	if flushQueuedMessages {
-		// Dump the lines we've queued up for printing.
-		for _, line := range r.queuedMessageLines {
-			if ansi.StringWidth(line) < r.width {
-				// We only erase the rest of the line when the line is shorter than
-				// the width of the terminal. When the cursor reaches the end of
-				// the line, any escape sequences that follow will only affect the
-				// last cell of the line.
-
-				// Removing previously rendered content at the end of line.
-				line = line + ansi.EraseLineRight
-			}
-
-			_, _ = buf.WriteString(line)
-			_, _ = buf.WriteString("\r\n")
-		}
-		// Clear the queued message lines.
-		r.queuedMessageLines = []string{}
+		r.writeQueuedMessages(buf)
 	}
 
 	// Paint new lines.
@@ -237,15 +445,17 @@ func (r *standardRenderer) flush() {
 		// Note that on Windows we only get the width of the window on
 		// program initialization, so after a resize this won't perform
 		// correctly (signal SIGWINCH is not supported on Windows).
-		if r.width > 0 {
-			line = ansi.Truncate(line, r.width, "")
+		if width := r.renderWidth(); width > 0 {
+			line = ansi.Truncate(line, width, "")
 		}
 
-		if ansi.StringWidth(line) < r.width {
+		if ansi.StringWidth(line) < r.width || r.shorterThanRendered(i, line) {
 			// We only erase the rest of the line when the line is shorter than
 			// the width of the terminal. When the cursor reaches the end of
 			// the line, any escape sequences that follow will only affect the
-			// last cell of the line.
+			// last cell of the line. Without the width, we erase what's left
+			// of the line previously rendered in its place, as happens when
+			// a line is updated in place.
 
 			// Removing previously rendered content at the end of line.
 			line = line + ansi.EraseLineRight
@@ -258,6 +468,12 @@ func (r *standardRenderer) flush() {
 		}
 	}
 
+	// Reset styles left open by the view so they don't bleed into the rest
+	// of the terminal.
+	if r.autoResetSGR && sgr.Open(buf.String()) {
+		buf.WriteString(sgr.Reset)
+	}
+
 	// Clearing left over content from last render.
 	if r.lastLinesRendered() > len(newLines) {
 		buf.WriteString(ansi.EraseScreenBelow)
@@ -280,16 +496,209 @@ func (r *standardRenderer) flush() {
 		buf.WriteByte('\r')
 	}
 
-	_, _ = r.out.Write(buf.Bytes())
+	r.finishFlush(buf, newLines, start)
+}
+
+// writeQueuedMessages writes the lines queued for printing to buf and clears
+// the queue.
+func (r *standardRenderer) writeQueuedMessages(buf *bytes.Buffer) {
+	// Dump the lines we've queued up for printing.
+	for _, line := range r.queuedMessageLines {
+		if ansi.StringWidth(line) < r.width {
+			// We only erase the rest of the line when the line is shorter than
+			// the width of the terminal. When the cursor reaches the end of
+			// the line, any escape sequences that follow will only affect the
+			// last cell of the line.
+
+			// Removing previously rendered content at the end of line.
+			line = line + ansi.EraseLineRight
+		}
+
+		_, _ = buf.WriteString(line)
+		_, _ = buf.WriteString("\r\n")
+	}
+	// Clear the queued message lines.
+	r.queuedMessageLines = []string{}
+}
+
+// flushPrintQueue writes the lines queued for printing right away, and
+// repaints the frame below them, instead of waiting for the next frame.
+// Without a frame on the screen, the lines are left for the next one.
+func (r *standardRenderer) flushPrintQueue() {
+	if len(r.queuedMessageLines) == 0 || r.altScreenActive || r.region != nil ||
+		r.frozen || r.lastRenderedLines == nil {
+		return
+	}
+
+	// Without a newer view, the printed lines push the last one down, so
+	// it's painted again.
+	if r.buf.Len() == 0 {
+		r.buf.WriteString(r.lastRender)
+	}
+	r.render()
+	r.flushOutput()
+}
+
+// finishFlush writes the output buffer of a frame consisting of the given
+// lines, recording how long it took since the flush started.
+func (r *standardRenderer) finishFlush(buf *bytes.Buffer, newLines []string, start time.Time) {
+	move, back := r.cursorPlacement()
+	buf.WriteString(move)
+	if r.synchronizedOutput {
+		buf.WriteString(ansi.ResetSynchronizedOutputMode)
+	}
+
+	r.writeOut(buf.Bytes())
+	r.cursorReturn = back
+	r.flushLatency.record(time.Since(start))
+	frame := atomic.AddUint64(&r.framesRendered, 1) - 1
+	if r.frameLog != nil {
+		_, _ = fmt.Fprintf(r.frameLog, "frame %d at %s\n%s\n\n",
+			frame, time.Now().Format(time.RFC3339Nano), strconv.Quote(buf.String()))
+	}
 	r.lastRender = r.buf.String()
 
 	// Save previously rendered lines for comparison in the next render. If we
 	// don't do this, we can't skip rendering lines that haven't changed.
 	// See https://github.com/charmbracelet/bubbletea/pull/1233
 	r.lastRenderedLines = newLines
+	if r.smartRepaint {
+		r.lastFrame = r.truncateLines(newLines)
+	}
 	r.buf.Reset()
 }
 
+// shorterThanRendered reports whether line is narrower than the i-th line of
+// the last frame, which it replaces.
+func (r *standardRenderer) shorterThanRendered(i int, line string) bool {
+	return i < len(r.lastRenderedLines) && ansi.StringWidth(line) < ansi.StringWidth(r.lastRenderedLines[i])
+}
+
+// cursorPlacement returns the sequences moving the cursor from where the
+// renderer leaves it after a frame to the position set with
+// SetCursorPosition, and back. They're empty if no position is set.
+func (r *standardRenderer) cursorPlacement() (move, back string) {
+	n := r.lastLinesRendered()
+	if r.region != nil {
+		n = r.region.height
+	}
+	if !r.cursorPlaced || n == 0 {
+		return "", ""
+	}
+	row, col := min(r.cursorRow, n-1), r.cursorCol
+
+	switch {
+	case r.region != nil:
+		// Regions are painted with absolute positions.
+		return ansi.CursorPosition(r.region.x+col+1, r.region.y+row+1), ""
+	case r.altScreenActive:
+		return ansi.CursorPosition(col+1, row+1), ansi.CursorPosition(0, n)
+	}
+
+	// Inline, the cursor is left at the beginning of the last line.
+	move, back = "\r", "\r"
+	if up := n - 1 - row; up > 0 {
+		move, back = ansi.CursorUp(up)+"\r", ansi.CursorDown(up)+"\r"
+	}
+	if col > 0 {
+		move += ansi.CursorForward(col)
+	}
+	return move, back
+}
+
+// paintRegion paints the given lines into the region and returns them as
+// painted. Every line of the region is positioned absolutely and padded to the
+// width of the region so nothing outside of it is touched. Lines that haven't
+// changed are skipped.
+func (r *standardRenderer) paintRegion(buf *bytes.Buffer, newLines []string) []string {
+	lines := make([]string, r.region.height)
+	for i := range lines {
+		var line string
+		if i < len(newLines) {
+			line = ansi.Truncate(newLines[i], r.region.width, "")
+		}
+		if w := ansi.StringWidth(line); w < r.region.width {
+			line += strings.Repeat(" ", r.region.width-w)
+		}
+		lines[i] = line
+
+		if i < len(r.lastRenderedLines) && r.lastRenderedLines[i] == line {
+			continue
+		}
+		buf.WriteString(ansi.CursorPosition(r.region.x+1, r.region.y+i+1))
+		buf.WriteString(line)
+	}
+	return lines
+}
+
+// frameLines splits the given view into the lines of a frame.
+func (r *standardRenderer) frameLines(view string) []string {
+	if width := r.renderWidth(); r.softWrap && width > 0 {
+		view = ansi.Hardwrap(view, width, true)
+	}
+	lines := strings.Split(view, "\n")
+
+	// Reserve the bottom row for the status line.
+	height := r.height
+	if r.statusLine != nil && height > 0 {
+		height--
+	}
+
+	// If we know the output's height, we can use it to determine how many
+	// lines we can render. We drop lines from the top of the render buffer if
+	// necessary, as we can't navigate the cursor into the terminal's scrollback
+	// buffer.
+	if r.height > 0 && len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+
+	// In full height inline mode we pad the frame to the height of the
+	// window so it always occupies the entire screen, much like the alt
+	// screen, while leaving the scrollback buffer intact. The status line
+	// needs the same padding in the alt screen to sit on the last row.
+	pad := r.fullHeightInline && !r.altScreenActive ||
+		r.statusLine != nil && r.altScreenActive
+	if pad && height > len(lines) {
+		lines = append(lines, make([]string, height-len(lines))...)
+	}
+
+	if r.statusLine != nil {
+		lines = append(lines, r.status)
+	}
+
+	return lines
+}
+
+// statusLine returns the first line of s, which is all a status line can
+// show.
+func statusLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// truncateLines returns the given lines truncated to the width of the
+// renderer, as they would appear on screen.
+func (r *standardRenderer) truncateLines(lines []string) []string {
+	width := r.renderWidth()
+	if width <= 0 {
+		return lines
+	}
+	truncated := make([]string, len(lines))
+	for i, line := range lines {
+		truncated[i] = ansi.Truncate(line, width, "")
+	}
+	return truncated
+}
+
+// renderWidth returns the width frames are rendered at: the width of the
+// window, capped to the maximum width unless the viewport is maximized.
+func (r *standardRenderer) renderWidth() int {
+	if r.maxWidth > 0 && !r.maximized && (r.width <= 0 || r.maxWidth < r.width) {
+		return r.maxWidth
+	}
+	return r.width
+}
+
 // lastLinesRendered returns the number of lines rendered lastly.
 func (r *standardRenderer) lastLinesRendered() int {
 	if r.altScreenActive {
@@ -312,10 +721,21 @@ func (r *standardRenderer) write(s string) {
 	if s == "" {
 		s = " "
 	}
+	if r.noColor {
+		s = stripColors(s)
+	}
 
 	_, _ = r.buf.WriteString(s)
 }
 
+// resetPalette resets the color palette if it was changed. The changed
+// entries are kept, for start to set them again.
+func (r *standardRenderer) resetPalette() {
+	if len(r.palette) > 0 {
+		r.execute(resetPaletteSeq)
+	}
+}
+
 func (r *standardRenderer) repaint() {
 	r.lastRender = ""
 	r.lastRenderedLines = nil
@@ -500,9 +920,35 @@ func (r *standardRenderer) reportFocus() bool {
 	return r.reportingFocus
 }
 
-// setWindowTitle sets the terminal window title.
+// pendingPrintLines returns the number of printed lines waiting for the next
+// flush.
+func (r *standardRenderer) pendingPrintLines() int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.altScreenActive {
+		return 0
+	}
+	return len(r.queuedMessageLines)
+}
+
+// setWindowTitle sets the terminal window title. Control characters are
+// removed so they can't end the sequence early and inject others.
 func (r *standardRenderer) setWindowTitle(title string) {
-	r.execute(ansi.SetWindowTitle(title))
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.execute(ansi.SetWindowTitle(sanitizeTitle(title)))
+}
+
+// sanitizeTitle removes control characters from a window title.
+func sanitizeTitle(title string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, title)
 }
 
 // setIgnoredLines specifies lines not to be touched by the standard Bubble Tea
@@ -534,7 +980,7 @@ func (r *standardRenderer) setIgnoredLines(from int, to int) {
 			buf.WriteString(ansi.CUU1)
 		}
 		buf.WriteString(ansi.CursorPosition(0, lastLinesRendered)) // put cursor back
-		_, _ = r.out.Write(buf.Bytes())
+		r.writeOut(buf.Bytes())
 	}
 }
 
@@ -577,15 +1023,17 @@ func (r *standardRenderer) insertTop(lines []string, topBoundary, bottomBoundary
 	buf := &bytes.Buffer{}
 
 	buf.WriteString(ansi.SetTopBottomMargins(topBoundary, bottomBoundary))
-	buf.WriteString(ansi.CursorPosition(0, topBoundary))
+	buf.WriteString(r.scrollAreaOrigin())
+	buf.WriteString(r.scrollAreaPosition(topBoundary, topBoundary))
 	buf.WriteString(ansi.InsertLine(len(lines)))
 	_, _ = buf.WriteString(strings.Join(lines, "\r\n"))
+	buf.WriteString(r.resetScrollAreaOrigin())
 	buf.WriteString(ansi.SetTopBottomMargins(0, r.height))
 
 	// Move cursor back to where the main rendering routine expects it to be
 	buf.WriteString(ansi.CursorPosition(0, r.lastLinesRendered()))
 
-	_, _ = r.out.Write(buf.Bytes())
+	r.writeOut(buf.Bytes())
 }
 
 // insertBottom effectively scrolls down. It inserts lines at the bottom of
@@ -607,14 +1055,49 @@ func (r *standardRenderer) insertBottom(lines []string, topBoundary, bottomBound
 	buf := &bytes.Buffer{}
 
 	buf.WriteString(ansi.SetTopBottomMargins(topBoundary, bottomBoundary))
-	buf.WriteString(ansi.CursorPosition(0, bottomBoundary))
+	buf.WriteString(r.scrollAreaOrigin())
+	buf.WriteString(r.scrollAreaPosition(bottomBoundary, topBoundary))
 	_, _ = buf.WriteString("\r\n" + strings.Join(lines, "\r\n"))
+	buf.WriteString(r.resetScrollAreaOrigin())
 	buf.WriteString(ansi.SetTopBottomMargins(0, r.height))
 
 	// Move cursor back to where the main rendering routine expects it to be
 	buf.WriteString(ansi.CursorPosition(0, r.lastLinesRendered()))
 
-	_, _ = r.out.Write(buf.Bytes())
+	r.writeOut(buf.Bytes())
+}
+
+// scrollAreaOrigin returns the sequence setting origin mode for painting into
+// a scroll area, if the renderer manages it.
+func (r *standardRenderer) scrollAreaOrigin() string {
+	switch {
+	case r.originMode == nil:
+		return ""
+	case *r.originMode:
+		return ansi.SetOriginMode
+	default:
+		return ansi.ResetOriginMode
+	}
+}
+
+// resetScrollAreaOrigin returns the sequence resetting origin mode after
+// painting into a scroll area, so the rest of the renderer can keep using
+// absolute positions.
+func (r *standardRenderer) resetScrollAreaOrigin() string {
+	if r.originMode != nil && *r.originMode {
+		return ansi.ResetOriginMode
+	}
+	return ""
+}
+
+// scrollAreaPosition returns the sequence moving the cursor to the given row
+// of a scroll area whose top margin is at row top. In origin mode rows are
+// counted from the top margin.
+func (r *standardRenderer) scrollAreaPosition(row, top int) string {
+	if r.originMode != nil && *r.originMode {
+		return ansi.CursorPosition(0, row-top+1)
+	}
+	return ansi.CursorPosition(0, row)
 }
 
 // handleMessages handles internal messages for the renderer.
@@ -631,7 +1114,12 @@ func (r *standardRenderer) handleMessages(msg Msg) {
 		r.mtx.Lock()
 		r.width = msg.Width
 		r.height = msg.Height
-		r.repaint()
+		if r.smartRepaint {
+			// Whether we need to repaint is decided on the next flush.
+			r.resized = true
+		} else {
+			r.repaint()
+		}
 		r.mtx.Unlock()
 
 	case clearScrollAreaMsg:
@@ -654,23 +1142,190 @@ func (r *standardRenderer) handleMessages(msg Msg) {
 		r.repaint()
 		r.mtx.Unlock()
 
+	case setIgnoredLinesMsg:
+		r.mtx.Lock()
+		from, to := max(msg.from, 0), min(msg.to, r.lastLinesRendered())
+		if from < to && r.ignoreLines == nil {
+			r.ignoreLines = make(map[int]struct{})
+		}
+		for i := from; i < to; i++ {
+			r.ignoreLines[i] = struct{}{}
+		}
+		r.mtx.Unlock()
+
+	case clearIgnoredLinesMsg:
+		r.mtx.Lock()
+		r.clearIgnoredLines()
+		r.repaint()
+		r.mtx.Unlock()
+
 	case scrollUpMsg:
 		r.insertTop(msg.lines, msg.topBoundary, msg.bottomBoundary)
 
 	case scrollDownMsg:
 		r.insertBottom(msg.lines, msg.topBoundary, msg.bottomBoundary)
 
+	case rawWriteMsg:
+		r.mtx.Lock()
+		r.execute(string(msg))
+		r.mtx.Unlock()
+
+	case requestSynchronizedOutputMsg:
+		r.mtx.Lock()
+		r.execute(ansi.RequestSynchronizedOutputMode)
+		r.mtx.Unlock()
+
+	case modeReportMsg:
+		if msg.mode == int(ansi.SynchronizedOutputMode) {
+			r.mtx.Lock()
+			r.synchronizedOutput = msg.supported()
+			r.mtx.Unlock()
+		}
+
+	case freezeUIMsg:
+		r.mtx.Lock()
+		r.frozen = true
+		if r.ticker != nil {
+			r.ticker.Stop()
+		}
+		r.mtx.Unlock()
+
+	case thawUIMsg:
+		r.mtx.Lock()
+		frozen := r.frozen
+		r.frozen = false
+		if frozen && r.ticker != nil {
+			r.ticker.Reset(r.framerate)
+		}
+		r.mtx.Unlock()
+		if frozen {
+			r.flush()
+		}
+
+	case setCursorPositionMsg:
+		r.mtx.Lock()
+		r.cursorPlaced = msg.row >= 0 && msg.col >= 0
+		r.cursorRow, r.cursorCol = msg.row, msg.col
+		if r.cursorPlaced {
+			r.cursorHidden = false
+			r.execute(ansi.ShowCursor)
+		}
+		move, back := r.cursorPlacement()
+		if r.cursorReturn != "" || move != "" {
+			r.writeOut([]byte(move))
+			r.cursorReturn = back
+		}
+		r.mtx.Unlock()
+
+	case setWorkingDirectoryMsg:
+		r.mtx.Lock()
+		r.execute(ansi.NotifyWorkingDirectory(msg.host, msg.path))
+		r.mtx.Unlock()
+
+	case notifyMsg:
+		r.mtx.Lock()
+		r.execute(notificationSequence(r.notificationFormat, msg.title, msg.body))
+		r.mtx.Unlock()
+
+	case terminalInfoRequestMsg:
+		r.mtx.Lock()
+		r.execute(requestTerminalVersion)
+		r.mtx.Unlock()
+
+	case setPaletteColorMsg:
+		r.mtx.Lock()
+		r.execute(msg.sequence())
+		if r.palette == nil {
+			r.palette = make(map[int]setPaletteColorMsg)
+		}
+		r.palette[msg.index] = msg
+		r.mtx.Unlock()
+
+	case resetPaletteMsg:
+		r.mtx.Lock()
+		r.execute(resetPaletteSeq)
+		clear(r.palette)
+		r.mtx.Unlock()
+
+	case cursorPositionRequestMsg:
+		r.mtx.Lock()
+		r.execute(ansi.RequestCursorPositionReport)
+		r.mtx.Unlock()
+
+	case enableLineWrapMsg:
+		r.mtx.Lock()
+		r.execute(ansi.SetAutoWrapMode)
+		r.lineWrapDisabled = false
+		r.mtx.Unlock()
+
+	case disableLineWrapMsg:
+		r.mtx.Lock()
+		r.execute(ansi.ResetAutoWrapMode)
+		r.lineWrapDisabled = true
+		r.mtx.Unlock()
+
+	case probeCapabilitiesMsg:
+		r.mtx.Lock()
+		r.execute(capabilityQueries)
+		r.mtx.Unlock()
+
+	case maximizeViewportMsg:
+		r.mtx.Lock()
+		r.maximized = true
+		r.repaint()
+		r.mtx.Unlock()
+
+	case restoreViewportMsg:
+		r.mtx.Lock()
+		r.maximized = false
+		r.repaint()
+		r.mtx.Unlock()
+
 	case printLineMessage:
-		if !r.altScreenActive {
-			lines := strings.Split(msg.messageBody, "\n")
+		if !r.altScreenActive && r.region == nil {
+			body := msg.messageBody
+			if r.noColor {
+				body = stripColors(body)
+			}
+			lines := strings.Split(body, "\n")
 			r.mtx.Lock()
 			r.queuedMessageLines = append(r.queuedMessageLines, lines...)
-			r.repaint()
 			r.mtx.Unlock()
 		}
+
+	case flushPrintQueueMsg:
+		r.mtx.Lock()
+		r.flushPrintQueue()
+		r.mtx.Unlock()
 	}
 }
 
+type setIgnoredLinesMsg struct {
+	from, to int
+}
+
+// SetIgnoredLines produces a command that keeps the renderer from repainting
+// the lines from from up to, but not including, to, counted from the top of
+// the last frame. Those lines keep showing what they showed when the command
+// ran, which saves repainting static parts of the view, such as a fixed header.
+// Lines beyond the last frame are left alone.
+//
+// Printing lines with Println and the like moves the frame down, and with it
+// whatever the ignored lines showed, so they're best not mixed.
+func SetIgnoredLines(from, to int) Cmd {
+	return func() Msg {
+		return setIgnoredLinesMsg{from: from, to: to}
+	}
+}
+
+type clearIgnoredLinesMsg struct{}
+
+// ClearIgnoredLines lets the renderer repaint the lines ignored with
+// SetIgnoredLines again.
+func ClearIgnoredLines() Msg {
+	return clearIgnoredLinesMsg{}
+}
+
 // HIGH-PERFORMANCE RENDERING STUFF
 
 type syncScrollAreaMsg struct {
@@ -788,3 +1443,36 @@ func Printf(template string, args ...interface{}) Cmd {
 		}
 	}
 }
+
+// FlushPrintQueue is a special command that prints the lines queued with
+// Println, Printf and PrintBlock right away, instead of with the next frame.
+// The last frame is repainted below them as it was, without rendering a new
+// one, so programs that print a lot don't have to change their view just to
+// get their output on the screen.
+//
+//	return m, tea.Sequence(tea.Println(entry), tea.FlushPrintQueue)
+//
+// If the altscreen is active no output will be printed.
+func FlushPrintQueue() Msg {
+	return flushPrintQueueMsg{}
+}
+
+// flushPrintQueueMsg is an internal message that signals to print the queued
+// lines. You can send a flushPrintQueueMsg with FlushPrintQueue.
+type flushPrintQueueMsg struct{}
+
+// PrintBlock prints the given lines above the Program as a single block. This
+// output is unmanaged by the program and will persist across renders by the
+// Program.
+//
+// Unlike consecutive calls to Println, the lines are guaranteed to be printed
+// together, without output from other sources in between.
+//
+// If the altscreen is active no output will be printed.
+func PrintBlock(lines []string) Cmd {
+	return func() Msg {
+		return printLineMessage{
+			messageBody: strings.Join(lines, "\n"),
+		}
+	}
+}
@@ -392,3 +392,63 @@ func TestProgramSuspendRefreshesWindowSizeAfterResume(t *testing.T) {
 		t.Fatalf("window size after resume = (%d, %d), want (132, 41)", msg.Width, msg.Height)
 	}
 }
+
+type resumeTestModel struct {
+	resumed atomic.Bool
+}
+
+func (m *resumeTestModel) Init() Cmd { return nil }
+
+func (m *resumeTestModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(ResumeMsg); ok {
+		m.resumed.Store(true)
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *resumeTestModel) View() string { return "" }
+
+func TestProgramSuspendMethod(t *testing.T) {
+	suspended := make(chan struct{}, 1)
+	original := suspendProcess
+	suspendProcess = func() { suspended <- struct{}{} }
+	t.Cleanup(func() { suspendProcess = original })
+
+	var in bytes.Buffer
+	m := &resumeTestModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(io.Discard), WithoutSignalHandler())
+	renderer := newSuspendTestRenderer()
+	p.renderer = renderer
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	p.Suspend()
+	select {
+	case <-suspended:
+	case <-time.After(time.Second):
+		t.Fatal("suspendProcess was not invoked")
+	}
+
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the program to quit once resumed")
+	}
+	if !m.resumed.Load() {
+		t.Fatal("expected a ResumeMsg after resuming")
+	}
+	// Once for the suspend cycle, and once as the program starts and stops.
+	if got := renderer.stopCalls(); got != 2 {
+		t.Fatalf("expected the renderer to be stopped twice, got %d", got)
+	}
+	if got := renderer.startCalls(); got != 2 {
+		t.Fatalf("expected the renderer to be started twice, got %d", got)
+	}
+}
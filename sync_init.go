@@ -0,0 +1,109 @@
+package tea
+
+import "time"
+
+// synchronousInitTimeout is how long WithSynchronousInit waits for Init's
+// commands before rendering the first frame anyway.
+var synchronousInitTimeout = time.Second
+
+// initDoneMsg is an internal message that signals the event loop has processed
+// the messages of Init's commands, see WithSynchronousInit.
+type initDoneMsg struct{}
+
+// runInit runs the command returned by Init until the deadline and returns the
+// messages it produced, in order, so they can be processed before the first
+// render, see WithSynchronousInit. Commands that didn't return before the
+// deadline, such as commands waiting on input, are returned to be run
+// asynchronously.
+func (p *Program) runInit(cmd Cmd, deadline time.Time) ([]Msg, Cmd) {
+	if cmd == nil {
+		return nil, nil
+	}
+
+	result, ok := p.callInitCmd(cmd, deadline)
+	if !ok {
+		return nil, func() Msg {
+			select {
+			case msg := <-result:
+				return msg
+			case <-p.ctx.Done():
+				return nil
+			}
+		}
+	}
+
+	switch msg := (<-result).(type) {
+	case nil:
+		return nil, nil
+
+	case BatchMsg:
+		var msgs []Msg
+		var rest []Cmd
+		for _, cmd := range msg {
+			m, r := p.runInit(cmd, deadline)
+			msgs = append(msgs, m...)
+			rest = append(rest, r)
+		}
+		return msgs, Batch(rest...)
+
+	case sequenceMsg:
+		var msgs []Msg
+		for i, cmd := range msg {
+			m, r := p.runInit(cmd, deadline)
+			msgs = append(msgs, m...)
+			if r != nil {
+				// Keep the order of the rest of the sequence.
+				return msgs, Sequence(append([]Cmd{r}, msg[i+1:]...)...)
+			}
+		}
+		return msgs, nil
+
+	default:
+		return []Msg{msg}, nil
+	}
+}
+
+// callInitCmd calls cmd and waits for its message until the deadline. If the
+// command returned in time, the message is ready to be received from the
+// returned channel; otherwise it will be once the command returns.
+func (p *Program) callInitCmd(cmd Cmd, deadline time.Time) (<-chan Msg, bool) {
+	result := make(chan Msg, 1)
+	go func() {
+		if !p.startupOptions.has(withoutCatchPanics) {
+			defer func() {
+				if r := recover(); r != nil {
+					p.recoverFromGoPanic(r)
+				}
+			}()
+		}
+		result <- p.callCmd(cmd)
+	}()
+
+	t := time.NewTimer(time.Until(deadline))
+	defer t.Stop()
+
+	select {
+	case msg := <-result:
+		result <- msg
+		return result, true
+	case <-t.C:
+		return result, false
+	case <-p.ctx.Done():
+		return result, false
+	}
+}
+
+// sendInitMsgs sends the messages of Init's commands to the event loop,
+// followed by an initDoneMsg, and holds off rendering until then.
+func (p *Program) sendInitMsgs(msgs []Msg) {
+	p.initializing = true
+	ch := make(chan struct{})
+	p.handlers.add(ch)
+
+	go func() {
+		defer close(ch)
+		for _, msg := range append(msgs, initDoneMsg{}) {
+			p.Send(msg)
+		}
+	}()
+}
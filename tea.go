@@ -10,6 +10,7 @@
 package tea
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
@@ -18,9 +19,11 @@ import (
 	"os/signal"
 	"runtime"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/charmbracelet/x/term"
 	"github.com/muesli/cancelreader"
@@ -36,6 +39,38 @@ var ErrProgramKilled = errors.New("program was killed")
 // signal, or when it receives a [InterruptMsg].
 var ErrInterrupted = errors.New("program was interrupted")
 
+// ErrTerminalDisconnected is returned by [Program.Run] when the terminal goes
+// away, for instance when an SSH connection drops, and the program can no
+// longer write to it. It wraps [ErrProgramKilled].
+var ErrTerminalDisconnected = fmt.Errorf("%w: terminal disconnected", ErrProgramKilled)
+
+// ErrSuspendNotSupported is reported on [Program.Errors] when the program is
+// asked to suspend on a platform without job control, such as Windows.
+var ErrSuspendNotSupported = errors.New("suspending is not supported on this platform")
+
+// ErrConflictingOptions is returned by [Program.Run] when the program was
+// created with options that contradict each other, such as WithoutRenderer and
+// WithAltScreen. The error describes the conflicting options.
+var ErrConflictingOptions = errors.New("conflicting program options")
+
+// ErrInvalidViewport is returned by [Program.Run] when the program was
+// created with a viewport that has no area or lies outside of the terminal,
+// see WithViewport.
+var ErrInvalidViewport = errors.New("invalid viewport")
+
+// ErrNilModel is reported on [Program.Errors] when the model's Update
+// returns a nil Model, in which case the program keeps the previous model.
+// With [WithStrictModel], Program.Run returns it instead.
+var ErrNilModel = errors.New("update returned a nil model")
+
+// ErrUnknownInput is reported on [Program.Errors] when the program receives
+// input it can't decode.
+var ErrUnknownInput = errors.New("unknown input")
+
+// errorsBufferSize is the number of non-fatal errors buffered for
+// Program.Errors.
+const errorsBufferSize = 16
+
 // Msg contain data from the result of a IO operation. Msgs trigger the update
 // function and, henceforth, the UI.
 type Msg interface{}
@@ -55,6 +90,15 @@ type Model interface {
 	View() string
 }
 
+// ModelReady can be implemented by a [Model] whose view isn't meaningful until
+// some asynchronous work, such as loading data, has completed. While Ready
+// returns false the model's view will not be rendered. Use [WithLoadingView]
+// to show a placeholder in the meantime.
+type ModelReady interface {
+	// Ready reports whether the model's view is ready to be rendered.
+	Ready() bool
+}
+
 // Cmd is an IO operation that returns a message when it's complete. If it's
 // nil it's considered a no-op. Use it for things like HTTP requests, timers,
 // saving and loading from disk, and so on.
@@ -86,7 +130,7 @@ func (i inputType) String() string {
 // generally set with ProgramOptions.
 //
 // The options here are treated as bits.
-type startupOptions int16
+type startupOptions int32
 
 func (s startupOptions) has(option startupOptions) bool {
 	return s&option != 0
@@ -105,6 +149,25 @@ const (
 	withoutCatchPanics
 	withoutBracketedPaste
 	withReportFocus
+	withFullHeightInline
+	withDebugOverlay
+	withGlobalPanicRestore
+	withSmartRepaint
+	withDeterministicBatch
+	withSynchronizedOutput
+	withConn
+	withKeepOutputOnExit
+	withoutResizeHandler
+	withLazyRawMode
+	withAutoResetSGR
+	withLatestWindowSizeOnly
+	withSimpleRenderer
+	withContinuousRendering
+	withSynchronousInit
+	withKeyRecording
+	withStrictModel
+	withSynchronizedOutputIfSupported
+	withSoftWrap
 )
 
 // channelHandlers manages the series of channels returned by various processes.
@@ -139,10 +202,22 @@ type Program struct {
 	// program can exit.
 	handlers channelHandlers
 
+	// resizeChecks tracks the size checks started in the background, see
+	// goCheckResize, which need to be waited on before the program can exit.
+	resizeChecks sync.WaitGroup
+
 	// Configuration options that will set as the program is initializing,
 	// treated as bits. These options can be set via various ProgramOptions.
 	startupOptions startupOptions
 
+	// startupBanner produces a line printed above the program once it
+	// starts, see WithStartupBanner.
+	startupBanner func() string
+
+	// initialCmds are run alongside the model's Init command at startup, see
+	// WithInitialCmds.
+	initialCmds []Cmd
+
 	// startupTitle is the title that will be set on the terminal when the
 	// program starts.
 	startupTitle string
@@ -169,6 +244,10 @@ type Program struct {
 	previousOutputState *term.State
 	renderer            renderer
 
+	// rendererMtx guards assigning the renderer while the program is
+	// running, for methods that access it from other goroutines.
+	rendererMtx sync.RWMutex
+
 	// the environment variables for the program, defaults to os.Environ().
 	environ []string
 
@@ -177,24 +256,188 @@ type Program struct {
 	// ttyInput is null if input is not a TTY.
 	ttyInput              term.File
 	previousTtyInputState *term.State
+	rawModeMtx            sync.Mutex
 	cancelReader          cancelreader.CancelReader
 	readLoopDone          chan struct{}
 
+	// session is the session running the program, if any, see Session.
+	// keepAltScreen is set when the alternate screen is left active for the
+	// session's next program.
+	session       *Session
+	keepAltScreen bool
+
 	// was the altscreen active before releasing the terminal?
 	altScreenWasActive bool
 	ignoreSignals      uint32
 
 	bpWasActive bool // was the bracketed paste mode active before releasing the terminal?
 	reportFocus bool // was focus reporting active before releasing the terminal?
+	inputEcho   bool // was input echo enabled with EnableEcho?
+
+	// shellCommand is printed to stdout on exit, see QueueShellCommand.
+	shellCommand string
+
+	// pendingSize is the latest window size sent while a
+	// latestWindowSizeMsg is pending, see WithLatestWindowSizeOnly.
+	pendingSizeMtx sync.Mutex
+	pendingSize    *WindowSizeMsg
 
 	filter func(Model, Msg) Msg
 
+	// errorHandler is called with the errors of ErrorMsgs, see
+	// WithErrorHandler.
+	errorHandler func(error) Cmd
+
+	// inputInterceptor handles raw input before the parser, see
+	// WithInputInterceptor.
+	inputInterceptor inputInterceptor
+
+	// escapeTimeout is how long to wait for the rest of an escape sequence,
+	// see WithEscapeTimeout.
+	escapeTimeout time.Duration
+
+	// recoveryModel replaces the model when it panics, see
+	// WithRecoveryModel. recovered is set once it has.
+	recoveryModel func(r interface{}) Model
+	recovered     bool
+
+	// slowUpdateWarning is called when Update takes longer than
+	// slowUpdateThreshold, see WithSlowUpdateWarning.
+	slowUpdateThreshold time.Duration
+	slowUpdateWarning   func(msgType string, took time.Duration)
+
+	// statusLine returns the line pinned to the bottom of every frame, see
+	// WithStatusLine.
+	statusLine func() string
+
+	// outputBufferSize is the size of the renderer's output buffer, see
+	// WithOutputBuffer.
+	outputBufferSize int
+
+	// originMode is whether to set or reset origin mode around scroll
+	// areas, see WithOriginMode.
+	originMode *bool
+
+	// slowOutputThreshold is how long writes to the output may take before
+	// the program is sent a SlowOutputMsg, see WithSlowOutputThreshold.
+	slowOutputThreshold time.Duration
+
+	// keyRecorder records the keys processed by the model, see
+	// WithKeyRecording and StartMacro.
+	keyRecorder keyRecorder
+
+	// notificationFormat is the escape sequence used by Notify, see
+	// WithNotificationFormat.
+	notificationFormat NotificationFormat
+
+	// commandTimeout is how long commands may run before they're abandoned,
+	// see WithCommandTimeout.
+	commandTimeout time.Duration
+
 	// fps is the frames per second we should set on the renderer, if
 	// applicable,
 	fps int
 
+	// frameLog, if set, receives an escaped copy of every rendered frame.
+	frameLog io.Writer
+
+	// maxWidth caps the width of frames rendered by the standard renderer,
+	// if set.
+	maxWidth int
+
+	// droppedMessages counts the messages dropped by TrySend, and
+	// droppedMessageHook is called with each of them.
+	droppedMessages    uint64
+	droppedMessageHook func(Msg)
+
+	// debugOverlay is set when the debug overlay is enabled.
+	debugOverlay *debugOverlay
+
+	// snapshots is the stack of model snapshots managed with PushSnapshot
+	// and PopSnapshot. It holds at most snapshotDepth snapshots and is only
+	// accessed from the event loop.
+	snapshots     [][]byte
+	snapshotDepth int
+
+	// loadingView is rendered in place of the model's view while a model
+	// implementing ModelReady isn't ready.
+	loadingView string
+
+	// viewTransform, if set, is applied to the model's view before it's
+	// rendered.
+	viewTransform func(string) string
+
+	// notifySignals relays incoming signals to a channel, like signal.Notify,
+	// which is used if it's nil. Tests set it to deliver signals on platforms
+	// that can't send them to the current process.
+	notifySignals func(c chan<- os.Signal, sig ...os.Signal)
+
+	// region is the area of the terminal to render into, see WithViewport.
+	region *region
+
+	// keyRepeatThrottle is the window in which repeated keys are dropped,
+	// see WithKeyRepeatThrottle. lastKey and lastKeyTime are the last key
+	// delivered and when; they're only accessed from the event loop.
+	keyRepeatThrottle time.Duration
+	lastKey           string
+	lastKeyTime       time.Time
+
+	// nonFatalErrors buffers the errors reported on Errors.
+	nonFatalErrors chan error
+
+	// minWidth and minHeight are the minimum terminal size, see
+	// WithMinimumSize.
+	minWidth  int
+	minHeight int
+
+	// signalMap overrides the messages sent for signals, see WithSignalMap.
+	signalMap map[os.Signal]func() Msg
+
 	// mouseMode is true if the program should enable mouse mode on Windows.
 	mouseMode bool
+
+	// cancelableCmds holds the cancel functions of the commands started with
+	// StartCmd that are still running. It's only accessed from the event loop.
+	cancelableCmds map[int]context.CancelFunc
+
+	// terminalInfoRequests are the TerminalInfo requests awaiting a response
+	// from the terminal. It's only accessed from the event loop.
+	terminalInfoRequests []*terminalInfoRequest
+
+	// cursorPositionRequests are the QueryCursorPosition requests awaiting a
+	// response from the terminal. It's only accessed from the event loop.
+	cursorPositionRequests []*cursorPositionRequest
+
+	// capabilityProbe is the ProbeCapabilities request awaiting responses
+	// from the terminal, if any. It's only accessed from the event loop.
+	capabilityProbe *capabilityProbe
+
+	// optionConflicts describes the options that contradict each other, see
+	// ErrConflictingOptions.
+	optionConflicts []string
+
+	// stopHooks are called once the program stops, see OnStop.
+	stopHooksMtx sync.Mutex
+	stopHooks    []func()
+
+	// loopStarted and loopStopped are when the event loop started and
+	// stopped, see Uptime.
+	uptimeMtx   sync.Mutex
+	loopStarted time.Time
+	loopStopped time.Time
+
+	// initializing is set while the event loop processes the messages of
+	// Init's commands, see WithSynchronousInit. It's only accessed from the
+	// event loop once it runs.
+	initializing bool
+
+	// pendingResponses are the responses to queries the program is waiting
+	// for, oldest first. It's only accessed from the event loop.
+	pendingResponses []pendingResponse
+
+	// unmatchedResponseHook is called with responses to queries the program
+	// didn't make, see WithUnmatchedResponseHook.
+	unmatchedResponseHook func(seq string)
 }
 
 // Quit is a special command that tells the Bubble Tea program to exit.
@@ -240,14 +483,17 @@ func Interrupt() Msg {
 // NewProgram creates a new Program.
 func NewProgram(model Model, opts ...ProgramOption) *Program {
 	p := &Program{
-		initialModel: model,
-		msgs:         make(chan Msg),
+		initialModel:   model,
+		msgs:           make(chan Msg),
+		nonFatalErrors: make(chan error, errorsBufferSize),
+		escapeTimeout:  defaultEscapeTimeout,
 	}
 
 	// Apply all options to the program.
 	for _, opt := range opts {
 		opt(p)
 	}
+	p.checkOptions()
 
 	// A context can be provided with a ProgramOption, but if none was provided
 	// we'll use the default background context.
@@ -281,9 +527,23 @@ func (p *Program) handleSignals() chan struct{} {
 	// caught here.
 	//
 	// SIGTERM is sent by unix utilities (like kill) to terminate a process.
+	//
+	// On Windows, Ctrl+C and Ctrl+Break are delivered as SIGINT, while closing
+	// the console window, logging off and shutting down are delivered as
+	// SIGTERM, so they map to the same messages.
 	go func() {
 		sig := make(chan os.Signal, 1)
-		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		notify := p.notifySignals
+		if notify == nil {
+			notify = signal.Notify
+		}
+		sigs := []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+		for s := range p.signalMap {
+			if s != syscall.SIGINT && s != syscall.SIGTERM {
+				sigs = append(sigs, s)
+			}
+		}
+		notify(sig, sigs...)
 		defer func() {
 			signal.Stop(sig)
 			close(ch)
@@ -295,13 +555,20 @@ func (p *Program) handleSignals() chan struct{} {
 				return
 
 			case s := <-sig:
-				if atomic.LoadUint32(&p.ignoreSignals) == 0 {
-					switch s {
-					case syscall.SIGINT:
-						p.msgs <- InterruptMsg{}
-					default:
-						p.msgs <- QuitMsg{}
-					}
+				if atomic.LoadUint32(&p.ignoreSignals) != 0 {
+					continue
+				}
+				msg := p.signalMsg(s)
+				if msg == nil {
+					continue
+				}
+				select {
+				case <-p.ctx.Done():
+					return
+				case p.msgs <- msg:
+				}
+				switch msg.(type) {
+				case QuitMsg, InterruptMsg:
 					return
 				}
 			}
@@ -311,16 +578,31 @@ func (p *Program) handleSignals() chan struct{} {
 	return ch
 }
 
+// signalMsg returns the message to send for the given signal.
+func (p *Program) signalMsg(s os.Signal) Msg {
+	if fn, ok := p.signalMap[s]; ok && fn != nil {
+		return fn()
+	}
+	if s == syscall.SIGINT {
+		return InterruptMsg{}
+	}
+	return QuitMsg{}
+}
+
 // handleResize handles terminal resize events.
 func (p *Program) handleResize() chan struct{} {
 	ch := make(chan struct{})
 
 	if p.ttyOutput != nil {
 		// Get the initial terminal size and send it to the program.
-		go p.checkResize()
+		go p.checkSize(true)
 
-		// Listen for window resizes.
-		go p.listenForResize(ch)
+		// Listen for window resizes, unless the host takes care of it.
+		if p.startupOptions.has(withoutResizeHandler) {
+			close(ch)
+		} else {
+			go p.listenForResize(ch)
+		}
 	} else {
 		close(ch)
 	}
@@ -361,7 +643,7 @@ func (p *Program) handleCommands(cmds chan Cmd) chan struct{} {
 						}()
 					}
 
-					msg := cmd() // this can be long.
+					msg := p.callCmd(cmd) // this can be long.
 					p.Send(msg)
 				}()
 			}
@@ -389,6 +671,48 @@ func (p *Program) eventLoop(model Model, cmds chan Cmd) (Model, error) {
 			return model, err
 
 		case msg := <-p.msgs:
+			// Acknowledge messages sent with SendSync. Messages are handled
+			// in order, so the message it sent has been processed by now.
+			if ack, ok := msg.(sendSyncAckMsg); ok {
+				close(ack)
+				continue
+			}
+
+			// Run commands injected with RunCmd like commands returned by
+			// Update.
+			if run, ok := msg.(runCmdMsg); ok {
+				select {
+				case <-p.ctx.Done():
+					return model, nil
+				case cmds <- run.cmd:
+				}
+				continue
+			}
+
+			// Deliver the results of cancelable commands unless they were
+			// canceled.
+			if result, ok := msg.(cmdResultMsg); ok {
+				if !p.forgetCmd(result.token) || result.msg == nil {
+					continue
+				}
+				msg = result.msg
+			}
+
+			// Deliver the latest window size, see WithLatestWindowSizeOnly.
+			if _, ok := msg.(latestWindowSizeMsg); ok {
+				msg = p.takeWindowSize()
+			}
+
+			// Drop keys repeated too quickly, see WithKeyRepeatThrottle.
+			if key, ok := msg.(KeyMsg); ok && p.throttleKey(key) {
+				continue
+			}
+
+			// Replayed keys are processed like any other key, see PlayMacro.
+			if key, ok := msg.(replayedKeyMsg); ok {
+				msg = KeyMsg(key)
+			}
+
 			// Filter messages.
 			if p.filter != nil {
 				msg = p.filter(model, msg)
@@ -397,8 +721,18 @@ func (p *Program) eventLoop(model Model, cmds chan Cmd) (Model, error) {
 				continue
 			}
 
+			if p.debugOverlay != nil {
+				p.debugOverlay.observe(msg)
+			}
+
 			// Handle special internal messages.
 			switch msg := msg.(type) {
+			case unknownInputByteMsg, unknownCSISequenceMsg:
+				p.reportError(fmt.Errorf("%w: %s", ErrUnknownInput, msg))
+
+			case InputErrorMsg:
+				p.reportError(msg)
+
 			case QuitMsg:
 				return model, nil
 
@@ -408,16 +742,25 @@ func (p *Program) eventLoop(model Model, cmds chan Cmd) (Model, error) {
 			case SuspendMsg:
 				if suspendSupported {
 					p.suspend()
+				} else {
+					p.reportError(ErrSuspendNotSupported)
 				}
 
 			case clearScreenMsg:
 				p.renderer.clearScreen()
 
 			case enterAltScreenMsg:
-				p.renderer.enterAltScreen()
+				if !p.renderer.altScreen() {
+					p.renderer.enterAltScreen()
+					// Let the model lay itself out for the new screen.
+					p.goCheckResize()
+				}
 
 			case exitAltScreenMsg:
-				p.renderer.exitAltScreen()
+				if p.renderer.altScreen() {
+					p.renderer.exitAltScreen()
+					p.goCheckResize()
+				}
 
 			case enableMouseCellMotionMsg, enableMouseAllMotionMsg:
 				switch msg.(type) {
@@ -454,6 +797,12 @@ func (p *Program) eventLoop(model Model, cmds chan Cmd) (Model, error) {
 			case hideCursorMsg:
 				p.renderer.hideCursor()
 
+			case enableEchoMsg:
+				p.setInputEcho(true)
+
+			case disableEchoMsg:
+				p.setInputEcho(false)
+
 			case enableBracketedPasteMsg:
 				p.renderer.enableBracketedPaste()
 
@@ -470,6 +819,10 @@ func (p *Program) eventLoop(model Model, cmds chan Cmd) (Model, error) {
 				// NB: this blocks.
 				p.exec(msg.cmd, msg.fn)
 
+			case readLineMsg:
+				// NB: this blocks.
+				p.readLine(msg)
+
 			case BatchMsg:
 				go p.execBatchMsg(msg)
 				continue
@@ -478,11 +831,100 @@ func (p *Program) eventLoop(model Model, cmds chan Cmd) (Model, error) {
 				go p.execSequenceMsg(msg)
 				continue
 
+			case streamMsg:
+				go p.runStream(msg)
+				continue
+
+			case contextCmdMsg:
+				go func() {
+					if !p.startupOptions.has(withoutCatchPanics) {
+						defer func() {
+							if r := recover(); r != nil {
+								p.recoverFromGoPanic(r)
+							}
+						}()
+					}
+					p.Send(p.runContextCmd(msg))
+				}()
+				continue
+
+			case startInputMsg:
+				if p.cancelReader == nil {
+					if err := p.initCancelReader(false); err != nil {
+						return model, err
+					}
+				}
+				continue
+
+			case initDoneMsg:
+				p.initializing = false
+
+			case queueShellCommandMsg:
+				p.shellCommand = string(msg)
+				continue
+
 			case setWindowTitleMsg:
-				p.SetWindowTitle(string(msg))
+				p.renderer.setWindowTitle(string(msg))
 
 			case windowSizeMsg:
-				go p.checkResize()
+				p.goCheckResize()
+
+			case startCmdMsg:
+				p.startCmd(msg)
+
+			case cancelCmdMsg:
+				p.forgetCmd(int(msg))
+
+			case pushSnapshotMsg:
+				p.pushSnapshot(model)
+
+			case popSnapshotMsg:
+				model = p.popSnapshot(model)
+
+			case debugDumpMsg:
+				go p.Send(msg.fn(debugDump(model)))
+
+			case terminalInfoRequestMsg:
+				p.requestTerminalInfo(msg.fn)
+				p.expectResponses(capabilityTerminalVersion)
+
+			case cursorPositionRequestMsg:
+				p.requestCursorPosition(msg.fn)
+				p.expectResponses(capabilityCursorPosition)
+
+			case probeCapabilitiesMsg:
+				p.handleCapabilityMsg(msg)
+				p.expectResponses(allCapabilities)
+
+			case capabilitiesTimeoutMsg:
+				p.handleCapabilityMsg(msg)
+
+			case requestSynchronizedOutputMsg:
+				p.expectResponses(capabilitySynchronizedOutput)
+
+			case cursorPositionMsg, backgroundColorMsg, keyboardFlagsMsg, terminalVersionMsg, modeReportMsg:
+				// Responses to queries are the program's business, not the
+				// model's.
+				if !p.matchResponse(msg.(terminalResponse)) {
+					continue
+				}
+				if r, ok := p.renderer.(*standardRenderer); ok {
+					r.handleMessages(msg)
+				}
+				switch msg := msg.(type) {
+				case terminalVersionMsg:
+					p.resolveTerminalInfo(nil, msg.name, msg.version)
+				case cursorPositionMsg:
+					p.resolveCursorPosition(nil, msg.row, msg.col)
+				}
+				p.handleCapabilityMsg(msg)
+				continue
+
+			case terminalInfoTimeoutMsg:
+				p.resolveTerminalInfo(msg.req, "", "")
+
+			case cursorPositionTimeoutMsg:
+				p.resolveCursorPosition(msg.req, 0, 0)
 			}
 
 			// Process internal messages for the renderer.
@@ -490,8 +932,41 @@ func (p *Program) eventLoop(model Model, cmds chan Cmd) (Model, error) {
 				r.handleMessages(msg)
 			}
 
+			// Let the model know when the terminal is too small. The
+			// renderer still needs the actual size.
+			if size, ok := msg.(WindowSizeMsg); ok && p.tooSmall(size) {
+				msg = TooSmallMsg{Width: size.Width, Height: size.Height}
+			}
+
+			var next Model
 			var cmd Cmd
-			model, cmd = model.Update(msg) // run update
+			var start time.Time
+			if p.slowUpdateWarning != nil {
+				start = time.Now()
+			}
+			key, isKey := msg.(KeyMsg)
+			recording := isKey && p.macroRecording()
+			next, cmd = p.update(model, msg) // run update
+			if p.slowUpdateWarning != nil {
+				p.warnSlowUpdate(msg, time.Since(start))
+			}
+			// Keep the previous model if Update returned none, see
+			// WithStrictModel.
+			if next == nil {
+				err := fmt.Errorf("%w: handling %T", ErrNilModel, msg)
+				if p.startupOptions.has(withStrictModel) {
+					return model, err
+				}
+				p.reportError(err)
+			} else {
+				model = next
+			}
+			if isKey {
+				p.recordKey(key, recording)
+			}
+			if err, ok := msg.(ErrorMsg); ok && err.Err != nil && p.errorHandler != nil {
+				cmd = Batch(cmd, p.errorHandler(err.Err))
+			}
 
 			select {
 			case <-p.ctx.Done():
@@ -499,9 +974,85 @@ func (p *Program) eventLoop(model Model, cmds chan Cmd) (Model, error) {
 			case cmds <- cmd: // process command (if any)
 			}
 
-			p.renderer.write(model.View()) // send view to renderer
+			// Hold off rendering until Init's messages are processed, see
+			// WithSynchronousInit.
+			if p.initializing {
+				continue
+			}
+
+			model, cmd = p.renderOrRecover(model) // send view to renderer
+			if cmd != nil {
+				select {
+				case <-p.ctx.Done():
+					return model, nil
+				case cmds <- cmd:
+				}
+			}
+		}
+	}
+}
+
+// warnSlowUpdate calls the slow update warning if handling msg took longer
+// than the threshold, see WithSlowUpdateWarning.
+func (p *Program) warnSlowUpdate(msg Msg, took time.Duration) {
+	if took > p.slowUpdateThreshold {
+		p.slowUpdateWarning(fmt.Sprintf("%T", msg), took)
+	}
+}
+
+// tooSmall reports whether the given size is below the minimum size.
+func (p *Program) tooSmall(size WindowSizeMsg) bool {
+	return (p.minWidth > 0 && size.Width < p.minWidth) ||
+		(p.minHeight > 0 && size.Height < p.minHeight)
+}
+
+// throttleKey reports whether the given key repeats the last key within the
+// key repeat throttle window and should be dropped.
+func (p *Program) throttleKey(key KeyMsg) bool {
+	if p.keyRepeatThrottle <= 0 || key.Paste {
+		return false
+	}
+	now := time.Now()
+	if key.String() == p.lastKey && now.Sub(p.lastKeyTime) < p.keyRepeatThrottle {
+		return true
+	}
+	p.lastKey, p.lastKeyTime = key.String(), now
+	return false
+}
+
+// view returns the view to render for the model: its view or the loading view
+// if it isn't ready, passed through the view transform. It reports false if
+// there's nothing to render.
+func (p *Program) view(model Model) (string, bool) {
+	var view string
+	if m, ok := model.(ModelReady); ok && !m.Ready() {
+		if p.loadingView == "" {
+			return "", false
 		}
+		view = p.loadingView
+	} else {
+		view = model.View()
 	}
+
+	if p.viewTransform != nil {
+		view = p.viewTransform(view)
+	}
+	return view, true
+}
+
+// render sends the model's view to the renderer. If the model implements
+// ModelReady and isn't ready yet, the loading view is rendered instead, if
+// any. Either view goes through the view transform and debug overlay.
+func (p *Program) render(model Model) {
+	view, ok := p.view(model)
+	if !ok {
+		return
+	}
+	if p.debugOverlay != nil {
+		p.debugOverlay.sample(p.renderedFrames(), time.Now())
+		view = p.debugOverlay.compose(view, p.frameWidth())
+	}
+	p.renderer.write(view)
 }
 
 func (p *Program) execSequenceMsg(msg sequenceMsg) {
@@ -518,7 +1069,7 @@ func (p *Program) execSequenceMsg(msg sequenceMsg) {
 		if cmd == nil {
 			continue
 		}
-		msg := cmd()
+		msg := p.callCmd(cmd)
 		switch msg := msg.(type) {
 		case BatchMsg:
 			p.execBatchMsg(msg)
@@ -539,6 +1090,37 @@ func (p *Program) execBatchMsg(msg BatchMsg) {
 		}()
 	}
 
+	exec := func(cmd Cmd) {
+		if !p.startupOptions.has(withoutCatchPanics) {
+			defer func() {
+				if r := recover(); r != nil {
+					p.recoverFromGoPanic(r)
+				}
+			}()
+		}
+
+		msg := p.callCmd(cmd)
+		switch msg := msg.(type) {
+		case BatchMsg:
+			p.execBatchMsg(msg)
+		case sequenceMsg:
+			p.execSequenceMsg(msg)
+		default:
+			p.Send(msg)
+		}
+	}
+
+	// With deterministic batches, execute commands in order on this
+	// goroutine.
+	if p.startupOptions.has(withDeterministicBatch) {
+		for _, cmd := range msg {
+			if cmd != nil {
+				exec(cmd)
+			}
+		}
+		return
+	}
+
 	// Execute commands one at a time.
 	var wg sync.WaitGroup
 	for _, cmd := range msg {
@@ -548,24 +1130,7 @@ func (p *Program) execBatchMsg(msg BatchMsg) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-
-			if !p.startupOptions.has(withoutCatchPanics) {
-				defer func() {
-					if r := recover(); r != nil {
-						p.recoverFromGoPanic(r)
-					}
-				}()
-			}
-
-			msg := cmd()
-			switch msg := msg.(type) {
-			case BatchMsg:
-				p.execBatchMsg(msg)
-			case sequenceMsg:
-				p.execSequenceMsg(msg)
-			default:
-				p.Send(msg)
-			}
+			exec(cmd)
 		}()
 	}
 
@@ -587,6 +1152,13 @@ func (p *Program) Run() (returnModel Model, returnErr error) {
 
 	defer p.cancel()
 
+	if len(p.optionConflicts) > 0 {
+		return p.initialModel, fmt.Errorf("%w: %s", ErrConflictingOptions, strings.Join(p.optionConflicts, ", "))
+	}
+	if r := p.region; r != nil && (r.width <= 0 || r.height <= 0 || r.x < 0 || r.y < 0) {
+		return p.initialModel, fmt.Errorf("%w: %dx%d at %d,%d", ErrInvalidViewport, r.width, r.height, r.x, r.y)
+	}
+
 	switch p.inputType {
 	case defaultInput:
 		p.input = os.Stdin
@@ -609,7 +1181,7 @@ func (p *Program) Run() (returnModel Model, returnErr error) {
 		if err != nil {
 			return p.initialModel, err
 		}
-		defer f.Close() //nolint:errcheck
+		defer p.closeInputTTY(f)
 		p.input = f
 
 	case ttyInput:
@@ -618,7 +1190,7 @@ func (p *Program) Run() (returnModel Model, returnErr error) {
 		if err != nil {
 			return p.initialModel, err
 		}
-		defer f.Close() //nolint:errcheck
+		defer p.closeInputTTY(f)
 		p.input = f
 
 	case customInput:
@@ -642,7 +1214,43 @@ func (p *Program) Run() (returnModel Model, returnErr error) {
 
 	// If no renderer is set use the standard one.
 	if p.renderer == nil {
-		p.renderer = newRenderer(p.output, p.startupOptions.has(withANSICompressor), p.fps)
+		p.rendererMtx.Lock()
+		if p.startupOptions.has(withSimpleRenderer) {
+			p.renderer = newSimpleRenderer(p.output)
+		} else {
+			p.renderer = newRenderer(p.output, p.startupOptions.has(withANSICompressor), p.fps)
+		}
+		p.rendererMtx.Unlock()
+	}
+	if r, ok := p.renderer.(*standardRenderer); ok {
+		r.fullHeightInline = p.startupOptions.has(withFullHeightInline)
+		r.smartRepaint = p.startupOptions.has(withSmartRepaint)
+		r.softWrap = p.startupOptions.has(withSoftWrap)
+		r.synchronizedOutput = p.startupOptions.has(withSynchronizedOutput)
+		r.keepOutput = p.startupOptions.has(withKeepOutputOnExit)
+		r.maxWidth = p.maxWidth
+		r.frameLog = p.frameLog
+		r.region = p.region
+		r.autoResetSGR = p.startupOptions.has(withAutoResetSGR)
+		r.noColor = noColor(p.environ)
+		r.statusLine = p.statusLine
+		r.originMode = p.originMode
+		r.notificationFormat = p.notificationFormat
+		r.onWriteError = p.handleOutputError
+		r.slowOutputThreshold = p.slowOutputThreshold
+		r.onSlowOutput = func() { p.Send(SlowOutputMsg{}) }
+		if p.outputBufferSize > 0 {
+			r.outputBuffer = bufio.NewWriterSize(r.out, p.outputBufferSize)
+		}
+	}
+	if p.startupOptions.has(withDebugOverlay) {
+		p.debugOverlay = &debugOverlay{}
+	}
+
+	// Restore the terminal on panics outside of Bubble Tea's control. This
+	// needs the renderer to be set.
+	if p.startupOptions.has(withGlobalPanicRestore) {
+		p.handlers.add(p.handlePanicRestore())
 	}
 
 	// Check if output is a TTY before entering raw mode, hiding the cursor and
@@ -655,9 +1263,17 @@ func (p *Program) Run() (returnModel Model, returnErr error) {
 	if p.startupTitle != "" {
 		p.renderer.setWindowTitle(p.startupTitle)
 	}
+	// Print the startup banner above the first frame, see
+	// WithStartupBanner.
+	if r, ok := p.renderer.(*standardRenderer); ok && p.startupBanner != nil && !p.startupOptions.has(withAltScreen) {
+		r.handleMessages(printLineMessage{messageBody: p.startupBanner()})
+	}
 	if p.startupOptions&withAltScreen != 0 {
 		p.renderer.enterAltScreen()
 	}
+	if p.startupOptions.has(withSynchronizedOutputIfSupported) {
+		go p.Send(requestSynchronizedOutputMsg{})
+	}
 	if p.startupOptions&withoutBracketedPaste == 0 {
 		p.renderer.enableBracketedPaste()
 	}
@@ -682,7 +1298,21 @@ func (p *Program) Run() (returnModel Model, returnErr error) {
 
 	// Initialize the program.
 	model := p.initialModel
-	if initCmd := model.Init(); initCmd != nil {
+	initCmd := model.Init()
+	if len(p.initialCmds) > 0 {
+		initCmd = Batch(append([]Cmd{initCmd}, p.initialCmds...)...)
+	}
+	var initMsgs []Msg
+	if p.startupOptions.has(withSynchronousInit) {
+		initMsgs, initCmd = p.runInit(initCmd, time.Now().Add(synchronousInitTimeout))
+	}
+	// With lazy raw mode, only start reading input once Init's commands have
+	// run, so programs that quit or exec right away never enter raw mode.
+	lazyInput := p.input != nil && p.startupOptions.has(withLazyRawMode)
+	if lazyInput {
+		initCmd = Sequence(initCmd, startInput)
+	}
+	if initCmd != nil {
 		ch := make(chan struct{})
 		p.handlers.add(ch)
 
@@ -696,11 +1326,15 @@ func (p *Program) Run() (returnModel Model, returnErr error) {
 		}()
 	}
 
-	// Render the initial view.
-	p.renderer.write(model.View())
+	// Render the initial view, unless it's to reflect Init's messages.
+	if len(initMsgs) > 0 {
+		p.sendInitMsgs(initMsgs)
+	} else {
+		p.render(model)
+	}
 
 	// Subscribe to user input.
-	if p.input != nil {
+	if p.input != nil && !lazyInput {
 		if err := p.initCancelReader(false); err != nil {
 			return model, err
 		}
@@ -712,8 +1346,15 @@ func (p *Program) Run() (returnModel Model, returnErr error) {
 	// Process commands.
 	p.handlers.add(p.handleCommands(cmds))
 
+	// Keep time-based views animating.
+	if p.startupOptions.has(withContinuousRendering) {
+		p.handlers.add(p.handleRenderTicks())
+	}
+
 	// Run event loop, handle updates and draw.
+	p.setLoopTime(&p.loopStarted)
 	model, err := p.eventLoop(model, cmds)
+	p.setLoopTime(&p.loopStopped)
 
 	if err == nil && len(p.errs) > 0 {
 		err = <-p.errs // Drain a leftover error in case eventLoop crashed
@@ -729,18 +1370,23 @@ func (p *Program) Run() (returnModel Model, returnErr error) {
 			// Return only that the program was killed (not the internal mechanism).
 			// The user does not know or need to care about the internal program context.
 			err = ErrProgramKilled
-		} else {
+		} else if !errors.Is(err, ErrProgramKilled) {
 			// Return that the program was killed and also the error that caused it.
 			err = fmt.Errorf("%w: %w", ErrProgramKilled, err)
 		}
 	} else {
 		// Graceful shutdown of the program (not killed):
 		// Ensure we rendered the final state of the model.
-		p.renderer.write(model.View())
+		p.render(model)
 	}
 
 	// Restore terminal state.
 	p.shutdown(killed)
+	p.runStopHooks()
+
+	if !killed && p.shellCommand != "" {
+		_, _ = fmt.Fprintln(os.Stdout, p.shellCommand)
+	}
 
 	return model, err
 }
@@ -772,9 +1418,278 @@ func (p *Program) Start() error {
 // If the program has already been terminated this will be a no-op, so it's safe
 // to send messages after the program has exited.
 func (p *Program) Send(msg Msg) {
+	if size, ok := msg.(WindowSizeMsg); ok && p.startupOptions.has(withLatestWindowSizeOnly) {
+		if !p.queueWindowSize(size) {
+			// The event loop will pick up this size with the pending one.
+			return
+		}
+		msg = latestWindowSizeMsg{}
+	}
+
+	select {
+	case <-p.ctx.Done():
+	case p.msgs <- msg:
+	}
+}
+
+// runCmdMsg is an internal message used to run a command with RunCmd.
+type runCmdMsg struct {
+	cmd Cmd
+}
+
+// RunCmd runs the given command as if Update had returned it, so its
+// message is delivered to Update, and batches, sequences and cancellation
+// work the same way. This is useful to trigger work, such as a refresh, from
+// outside the program, where Send would only deliver a message.
+//
+// Like Send, it blocks until the program has started, and is a no-op once
+// the program has exited.
+func (p *Program) RunCmd(cmd Cmd) {
+	if cmd == nil {
+		return
+	}
+	p.Send(runCmdMsg{cmd: cmd})
+}
+
+// latestWindowSizeMsg is sent in place of window sizes with
+// WithLatestWindowSizeOnly. The event loop replaces it with the latest size
+// sent in the meantime.
+type latestWindowSizeMsg struct{}
+
+// queueWindowSize records the latest window size and reports whether a
+// latestWindowSizeMsg has to be sent for it, that is if none is pending.
+func (p *Program) queueWindowSize(size WindowSizeMsg) bool {
+	p.pendingSizeMtx.Lock()
+	defer p.pendingSizeMtx.Unlock()
+	pending := p.pendingSize != nil
+	p.pendingSize = &size
+	return !pending
+}
+
+// takeWindowSize returns the latest window size queued with
+// queueWindowSize.
+func (p *Program) takeWindowSize() WindowSizeMsg {
+	p.pendingSizeMtx.Lock()
+	defer p.pendingSizeMtx.Unlock()
+	size := *p.pendingSize
+	p.pendingSize = nil
+	return size
+}
+
+// sendSyncAckMsg is sent by SendSync after its message. The event loop closes
+// the channel when it receives it.
+type sendSyncAckMsg chan struct{}
+
+// SendSync sends a message to the main update function and blocks until it
+// has been processed, that is until Update has returned and the resulting
+// command, if any, has been scheduled. This is useful in tests and scripted
+// drivers that would otherwise need to sleep or poll.
+//
+// It returns false if the program terminated before the message was
+// processed. This is also the case when msg itself quits the program, such as
+// a [QuitMsg].
+//
+// SendSync must not be called from Update or View, which would wait for
+// themselves forever.
+func (p *Program) SendSync(msg Msg) bool {
+	ack := make(sendSyncAckMsg)
+	for _, m := range []Msg{msg, ack} {
+		select {
+		case <-p.ctx.Done():
+			return false
+		case p.msgs <- m:
+		}
+	}
+
+	select {
+	case <-p.ctx.Done():
+		return false
+	case <-ack:
+		return true
+	}
+}
+
+// SendAfter sends a message to the program after the given duration has
+// elapsed, without blocking the caller. This saves you from spawning your own
+// goroutines to send delayed messages.
+//
+// If the program has terminated by the time the duration has elapsed the
+// message is discarded.
+func (p *Program) SendAfter(d time.Duration, msg Msg) {
+	go func() {
+		t := time.NewTimer(d)
+		defer t.Stop()
+
+		select {
+		case <-p.ctx.Done():
+		case <-t.C:
+			p.Send(msg)
+		}
+	}()
+}
+
+// TrySend is like [Program.Send], but never blocks. If the program can't
+// accept the message right away, because it's busy processing other messages,
+// the message is dropped and TrySend returns false. Dropped messages are
+// counted, see [Program.DroppedMessages], and passed to the hook set with
+// [WithDroppedMessageHook], if any.
+//
+// If the program has already been terminated this will be a no-op that
+// returns false.
+func (p *Program) TrySend(msg Msg) bool {
 	select {
 	case <-p.ctx.Done():
+		return false
+	default:
+	}
+
+	select {
 	case p.msgs <- msg:
+		return true
+	default:
+		atomic.AddUint64(&p.droppedMessages, 1)
+		if p.droppedMessageHook != nil {
+			p.droppedMessageHook(msg)
+		}
+		return false
+	}
+}
+
+// DroppedMessages returns the number of messages that were dropped by
+// [Program.TrySend] because the program couldn't keep up.
+func (p *Program) DroppedMessages() uint64 {
+	return atomic.LoadUint64(&p.droppedMessages)
+}
+
+// SetSize sets the size of the window, delivering a WindowSizeMsg as if the
+// window was resized. It's meant for hosts that watch for resizes themselves,
+// see WithoutResizeHandler.
+func (p *Program) SetSize(width, height int) {
+	p.Send(WindowSizeMsg{Width: width, Height: height})
+}
+
+// InputFd returns the file descriptor of the program's input, if it's a file
+// such as a TTY. This is useful to pass the terminal on to a child process.
+// Note that if the program opens a TTY for input, see WithInputTTY, it's only
+// known once the program runs.
+func (p *Program) InputFd() (uintptr, bool) {
+	if f, ok := p.input.(interface{ Fd() uintptr }); ok {
+		return f.Fd(), true
+	}
+	return 0, false
+}
+
+// InteractiveInput reports whether the program's input is a TTY, that is,
+// whether it reads from someone at a keyboard rather than from piped or
+// redirected input. Note that the input is only resolved once the program
+// runs, and that by default the program opens a TTY for input when standard
+// input isn't one, see WithInput.
+func (p *Program) InteractiveInput() bool {
+	f, ok := p.input.(term.File)
+	return ok && term.IsTerminal(f.Fd())
+}
+
+// OutputFd returns the file descriptor of the program's output, if it's a
+// file such as a TTY.
+func (p *Program) OutputFd() (uintptr, bool) {
+	if f, ok := p.output.(interface{ Fd() uintptr }); ok {
+		return f.Fd(), true
+	}
+	return 0, false
+}
+
+// Errors returns a channel reporting errors that don't stop the program as
+// they happen, such as input that can't be decoded. Errors that stop the
+// program are returned by Run instead. If the errors aren't received in time,
+// the oldest ones are dropped.
+func (p *Program) Errors() <-chan error {
+	return p.nonFatalErrors
+}
+
+// reportError reports a non-fatal error on the Errors channel, dropping the
+// oldest error if it's full.
+func (p *Program) reportError(err error) {
+	for {
+		select {
+		case p.nonFatalErrors <- err:
+			return
+		default:
+		}
+		select {
+		case <-p.nonFatalErrors:
+		default:
+		}
+	}
+}
+
+// Output returns the writer the program renders to, as set with WithOutput,
+// or stdout by default. It's meant to write to the terminal before or after
+// the program runs, for example to print a banner. While the program runs,
+// writing to it races with the renderer; use Println or Printf instead.
+func (p *Program) Output() io.Writer {
+	return p.output
+}
+
+// OnStop registers fn to be called once the program stops and the terminal
+// has been restored, whether it quit, was killed or the terminal disconnected,
+// for instance to persist state. Functions are called in the order they were
+// registered, before Run returns.
+func (p *Program) OnStop(fn func()) {
+	p.stopHooksMtx.Lock()
+	defer p.stopHooksMtx.Unlock()
+	p.stopHooks = append(p.stopHooks, fn)
+}
+
+// runStopHooks calls the functions registered with OnStop.
+func (p *Program) runStopHooks() {
+	p.stopHooksMtx.Lock()
+	hooks := p.stopHooks
+	p.stopHooksMtx.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// Context returns a context that's done once the program stops, whether it
+// quit, was killed or panicked. Use it to tie background work to the
+// program's lifetime. It's derived from the context set with WithContext.
+func (p *Program) Context() context.Context {
+	return p.ctx
+}
+
+// AltScreenActive reports whether the alternate screen buffer is currently
+// active. Note that output from Println and Printf is not shown while the
+// alternate screen is active.
+//
+// It always returns false if the program doesn't use a renderer.
+func (p *Program) AltScreenActive() bool {
+	p.rendererMtx.RLock()
+	r := p.renderer
+	p.rendererMtx.RUnlock()
+
+	if r == nil {
+		return false
+	}
+	return r.altScreen()
+}
+
+// Repaint requests a full repaint of the view on the next frame, even if it
+// hasn't changed. This is useful when something other than the program has
+// drawn over the terminal. It's safe to call concurrently and does nothing
+// when the renderer is disabled.
+func (p *Program) Repaint() {
+	p.rendererMtx.RLock()
+	r := p.renderer
+	p.rendererMtx.RUnlock()
+
+	switch r := r.(type) {
+	case nil:
+	case *standardRenderer:
+		r.mtx.Lock()
+		r.repaint()
+		r.mtx.Unlock()
+	default:
+		r.repaint()
 	}
 }
 
@@ -789,6 +1704,18 @@ func (p *Program) Quit() {
 	p.Send(Quit())
 }
 
+// Suspend suspends the program to the shell, much like pressing ctrl+z does in
+// programs that don't read raw input. The terminal is released until the
+// program is resumed, upon which a [ResumeMsg] is delivered. On platforms
+// without job control, such as Windows, ErrSuspendNotSupported is reported on
+// Errors instead.
+//
+// This is a convenience for sending the [Suspend] command's message from
+// outside the program.
+func (p *Program) Suspend() {
+	p.Send(Suspend())
+}
+
 // Kill signals the program to stop immediately and restore the former terminal state.
 // The final render that you would normally see when quitting will be skipped.
 // [program.Run] returns a [ErrProgramKilled] error.
@@ -796,6 +1723,28 @@ func (p *Program) Kill() {
 	p.cancel()
 }
 
+// Uptime returns how long the program has been running, counted from the
+// start of its event loop. It's zero before the program runs, and once the
+// program has stopped it keeps returning how long it ran.
+func (p *Program) Uptime() time.Duration {
+	p.uptimeMtx.Lock()
+	defer p.uptimeMtx.Unlock()
+	switch {
+	case p.loopStarted.IsZero():
+		return 0
+	case !p.loopStopped.IsZero():
+		return p.loopStopped.Sub(p.loopStarted)
+	}
+	return time.Since(p.loopStarted)
+}
+
+// setLoopTime sets t, either loopStarted or loopStopped, to the current time.
+func (p *Program) setLoopTime(t *time.Time) {
+	p.uptimeMtx.Lock()
+	*t = time.Now()
+	p.uptimeMtx.Unlock()
+}
+
 // Wait waits/blocks until the underlying Program finished shutting down.
 func (p *Program) Wait() {
 	<-p.finished
@@ -812,6 +1761,7 @@ func (p *Program) shutdown(kill bool) {
 
 	// Wait for all handlers to finish.
 	p.handlers.shutdown()
+	p.resizeChecks.Wait()
 
 	// Check if the cancel reader has been setup before waiting and closing.
 	if p.cancelReader != nil {
@@ -832,6 +1782,9 @@ func (p *Program) shutdown(kill bool) {
 		}
 	}
 
+	if p.session != nil {
+		p.session.keep(p)
+	}
 	_ = p.restoreTerminalState()
 }
 
@@ -888,8 +1841,13 @@ func (p *Program) RestoreTerminal() error {
 	if err := p.initTerminal(); err != nil {
 		return err
 	}
-	if err := p.initCancelReader(false); err != nil {
-		return err
+	if p.inputEcho {
+		p.setInputEcho(true)
+	}
+	if p.input != nil {
+		if err := p.initCancelReader(false); err != nil {
+			return err
+		}
 	}
 	if p.altScreenWasActive {
 		p.renderer.enterAltScreen()
@@ -911,7 +1869,7 @@ func (p *Program) RestoreTerminal() error {
 	// process was at the foreground, in which case we may not have received
 	// SIGWINCH. Detect any size change now and propagate the new size as
 	// needed.
-	go p.checkResize()
+	p.goCheckResize()
 
 	return nil
 }
@@ -939,3 +1897,32 @@ func (p *Program) Printf(template string, args ...interface{}) {
 		messageBody: fmt.Sprintf(template, args...),
 	}
 }
+
+// PrintBlock prints the given lines above the Program as a single block. This
+// output is unmanaged by the program and will persist across renders by the
+// Program.
+//
+// Unlike consecutive calls to Println, the lines are guaranteed to be printed
+// together, even when other goroutines are printing at the same time.
+//
+// If the altscreen is active no output will be printed.
+func (p *Program) PrintBlock(lines []string) {
+	p.msgs <- printLineMessage{
+		messageBody: strings.Join(lines, "\n"),
+	}
+}
+
+// PendingPrintLines returns the number of lines printed with Println, Printf
+// or PrintBlock that are waiting to be written with the next frame. Apps
+// printing a lot can use it to throttle their output when the renderer falls
+// behind. It's always 0 while the alt screen is active, where printed lines
+// are dropped, and with renderers other than the standard one.
+func (p *Program) PendingPrintLines() int {
+	p.rendererMtx.RLock()
+	defer p.rendererMtx.RUnlock()
+
+	if r, ok := p.renderer.(*standardRenderer); ok {
+		return r.pendingPrintLines()
+	}
+	return 0
+}
@@ -4,6 +4,7 @@
 package tea
 
 import (
+	"os"
 	"sync/atomic"
 	"syscall"
 	"testing"
@@ -91,3 +92,24 @@ func TestHandleSignalsHonorsIgnoreSignals(t *testing.T) {
 	p.cancel()
 	waitForSignalHandler(t, done)
 }
+
+func TestHandleSignalsSignalMap(t *testing.T) {
+	p := newSignalTestProgram(t)
+	WithSignalMap(map[os.Signal]func() Msg{
+		syscall.SIGTERM: Interrupt,
+	})(p)
+
+	done := p.handleSignals()
+	waitForSignalHandlerReady()
+	sendSignal(t, syscall.SIGTERM)
+
+	select {
+	case msg := <-p.msgs:
+		if _, ok := msg.(InterruptMsg); !ok {
+			t.Fatalf("expected InterruptMsg, got %T", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for InterruptMsg")
+	}
+	waitForSignalHandler(t, done)
+}
@@ -0,0 +1,95 @@
+//go:build windows
+// +build windows
+
+package tea
+
+import (
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// captureSignals makes the program's signal handler hand its signal channel
+// to the test, so signals can be delivered without involving the console.
+func captureSignals(p *Program) chan chan<- os.Signal {
+	captured := make(chan chan<- os.Signal, 1)
+	p.notifySignals = func(c chan<- os.Signal, sig ...os.Signal) {
+		signal.Notify(c, sig...)
+		captured <- c
+	}
+	return captured
+}
+
+func deliverSignal(t *testing.T, captured chan chan<- os.Signal, sig os.Signal) {
+	t.Helper()
+	select {
+	case c := <-captured:
+		c <- sig
+	case <-time.After(time.Second):
+		t.Fatalf("signal handler did not start in time")
+	}
+}
+
+func TestHandleSignalsWindows(t *testing.T) {
+	tests := []struct {
+		name string
+		sig  os.Signal
+		want Msg
+	}{
+		{"interrupt", os.Interrupt, InterruptMsg{}},
+		{"console close", syscall.SIGTERM, QuitMsg{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewProgram(nil, WithoutRenderer())
+			captured := captureSignals(p)
+			p.msgs = make(chan Msg, 1)
+			t.Cleanup(p.cancel)
+
+			done := p.handleSignals()
+			deliverSignal(t, captured, test.sig)
+
+			select {
+			case msg := <-p.msgs:
+				if msg != test.want {
+					t.Fatalf("expected %T, got %T", test.want, msg)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("timed out waiting for %T", test.want)
+			}
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatalf("signal handler did not terminate in time")
+			}
+		})
+	}
+}
+
+func TestHandleSignalsWindowsHonorsIgnoreSignals(t *testing.T) {
+	p := NewProgram(nil, WithoutRenderer())
+	captured := captureSignals(p)
+	p.msgs = make(chan Msg, 1)
+	atomic.StoreUint32(&p.ignoreSignals, 1)
+
+	done := p.handleSignals()
+	deliverSignal(t, captured, os.Interrupt)
+
+	select {
+	case msg := <-p.msgs:
+		t.Fatalf("expected no message while ignoring signals, got %T", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	p.cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("signal handler did not terminate in time")
+	}
+}
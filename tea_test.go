@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"os"
+	"reflect"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -78,6 +81,31 @@ func (m *testModel) View() string {
 	return "success\n"
 }
 
+// recordMessages records the messages passed on to the model's Update, in
+// the order they're delivered. Messages the program handles on its own, such
+// as batches or QuitMsg, never reach Update and aren't recorded. It must be
+// called before the program runs, and the messages read after it exited.
+func recordMessages(p *Program) *[]Msg {
+	var msgs []Msg
+	p.initialModel = recordingModel{Model: p.initialModel, msgs: &msgs}
+	return &msgs
+}
+
+// recordingModel wraps a model, recording the messages passed to its Update.
+type recordingModel struct {
+	Model
+	msgs *[]Msg
+}
+
+func (m recordingModel) Update(msg Msg) (Model, Cmd) {
+	*m.msgs = append(*m.msgs, msg)
+	next, cmd := m.Model.Update(msg)
+	if next == nil {
+		return nil, cmd
+	}
+	return recordingModel{Model: next, msgs: m.msgs}, cmd
+}
+
 func waitForModelExecution(t *testing.T, m *testModel) {
 	t.Helper()
 	deadline := time.Now().Add(2 * time.Second)
@@ -110,6 +138,111 @@ func TestTeaModel(t *testing.T) {
 	}
 }
 
+func TestTeaInputInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+	in.Write([]byte("\xfe\x01\xfe\x01q"))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 3*time.Second)
+	defer cancel()
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf), WithContext(ctx),
+		WithInputInterceptor(func(raw []byte) (int, []Msg) {
+			if bytes.HasPrefix(raw, []byte{0xfe, 0x01}) {
+				return 2, []Msg{incrementMsg{}}
+			}
+			return 0, nil
+		}))
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.counter.Load() != 2 {
+		t.Fatalf("expected the custom code to be intercepted twice, got %v", m.counter.Load())
+	}
+}
+
+type slowUpdateMsg struct{}
+
+type slowUpdateModel struct {
+	testModel
+}
+
+func (m *slowUpdateModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(slowUpdateMsg); ok {
+		time.Sleep(20 * time.Millisecond)
+		return m, nil
+	}
+	_, cmd := m.testModel.Update(msg)
+	return m, cmd
+}
+
+func TestTeaSlowUpdateWarning(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	const threshold = 10 * time.Millisecond
+	var warnings []string
+	var took time.Duration
+
+	m := &slowUpdateModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf), WithSlowUpdateWarning(threshold, func(msgType string, d time.Duration) {
+		warnings = append(warnings, msgType)
+		took = d
+	}))
+	go p.Send(sequenceMsg{
+		func() Msg { return incrementMsg{} },
+		func() Msg { return slowUpdateMsg{} },
+		Quit,
+	})
+
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"tea.slowUpdateMsg"}; !reflect.DeepEqual(warnings, want) {
+		t.Fatalf("expected warnings for %v, got %v", want, warnings)
+	}
+	if took < threshold {
+		t.Fatalf("expected the reported duration to be at least %v, got %v", threshold, took)
+	}
+}
+
+type renderTickModel struct {
+	testModel
+	ticks int
+}
+
+func (m *renderTickModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(RenderTickMsg); ok {
+		m.ticks++
+		if m.ticks == 3 {
+			return m, Quit
+		}
+		return m, nil
+	}
+	_, cmd := m.testModel.Update(msg)
+	return m, cmd
+}
+
+func TestTeaContinuousRendering(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 3*time.Second)
+	defer cancel()
+
+	m := &renderTickModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf), WithContext(ctx), WithContinuousRendering(), WithFPS(120))
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if m.ticks != 3 {
+		t.Fatalf("expected 3 render ticks, got %d", m.ticks)
+	}
+}
+
 func TestTeaQuit(t *testing.T) {
 	var buf bytes.Buffer
 	var in bytes.Buffer
@@ -415,6 +548,48 @@ func TestTeaBatchMsg(t *testing.T) {
 	}
 }
 
+type orderTestModel struct {
+	want int
+	got  []int
+}
+
+func (m *orderTestModel) Init() Cmd { return nil }
+
+func (m *orderTestModel) Update(msg Msg) (Model, Cmd) {
+	if i, ok := msg.(int); ok {
+		m.got = append(m.got, i)
+		if len(m.got) == m.want {
+			return m, Quit
+		}
+	}
+	return m, nil
+}
+
+func (m *orderTestModel) View() string { return "" }
+
+func TestTeaDeterministicBatchMsg(t *testing.T) {
+	c := func(i int) Cmd {
+		return func() Msg { return i }
+	}
+
+	for run := 0; run < 50; run++ {
+		var buf bytes.Buffer
+		var in bytes.Buffer
+
+		m := &orderTestModel{want: 5}
+		p := NewProgram(m, WithInput(&in), WithOutput(&buf), WithDeterministicBatch())
+		go p.Send(BatchMsg{c(0), c(1), Batch(c(2), c(3)), nil, c(4)})
+
+		if _, err := p.Run(); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(m.got, []int{0, 1, 2, 3, 4}) {
+			t.Fatalf("run %d: expected messages in order, got %v", run, m.got)
+		}
+	}
+}
+
 func TestTeaSequenceMsg(t *testing.T) {
 	var buf bytes.Buffer
 	var in bytes.Buffer
@@ -499,6 +674,440 @@ func TestTeaSend(t *testing.T) {
 	p.Send(Quit())
 }
 
+func TestTeaViewTransform(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf), WithViewTransform(func(view string) string {
+		return "[" + view + "]"
+	}))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	waitForModelExecution(t, m)
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	if out := buf.String(); !strings.Contains(out, "[success\r\n]") {
+		t.Fatalf("expected transformed view in output, got %q", out)
+	}
+}
+
+func TestTeaViewTransformLoadingView(t *testing.T) {
+	var in bytes.Buffer
+	r := &frameRecorder{}
+	p := NewProgram(&readyTestModel{}, WithInput(&in), WithLoadingView("loading..."), WithViewTransform(func(view string) string {
+		return "[" + view + "]"
+	}))
+	p.renderer = r
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	frames := r.recorded()
+	if len(frames) == 0 || frames[0] != "[loading...]" {
+		t.Fatalf("expected the transformed loading view to render first, got %q", frames)
+	}
+	if last := frames[len(frames)-1]; last != "[success\n]" {
+		t.Fatalf("expected the transformed view once ready, got %q", frames)
+	}
+}
+
+func TestTeaFrameLog(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+	var log bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf), WithFrameLog(&log))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	waitForModelExecution(t, m)
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	out := log.String()
+	if !strings.HasPrefix(out, "frame 0 at ") {
+		t.Fatalf("expected frame log to start with a frame header, got %q", out)
+	}
+	if !strings.Contains(out, `success\r\n`) {
+		t.Fatalf("expected escaped frame content in frame log, got %q", out)
+	}
+}
+
+func TestTeaSendAfter(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	p.SendAfter(10*time.Millisecond, incrementMsg{})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for m.counter.Load() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("delayed message was never delivered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	p.SendAfter(20*time.Millisecond, incrementMsg{})
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if got := m.counter.Load(); got != 1 {
+		t.Fatalf("expected counter to be 1 after quitting, got %v", got)
+	}
+}
+
+func TestTeaSendSync(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	for i := 1; i <= 3; i++ {
+		if !p.SendSync(incrementMsg{}) {
+			t.Fatal("expected program to be running")
+		}
+		if got := m.counter.Load(); got != i {
+			t.Fatalf("expected counter to be %d, got %v", i, got)
+		}
+	}
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	// sending a message after program has quit doesn't block
+	if p.SendSync(incrementMsg{}) {
+		t.Fatal("expected SendSync to report that the program has quit")
+	}
+}
+
+type keyCountModel struct {
+	keys []string
+}
+
+func (m *keyCountModel) Init() Cmd { return nil }
+
+func (m *keyCountModel) Update(msg Msg) (Model, Cmd) {
+	if msg, ok := msg.(KeyMsg); ok {
+		m.keys = append(m.keys, msg.String())
+	}
+	return m, nil
+}
+
+func (m *keyCountModel) View() string { return "" }
+
+func TestTeaKeyRepeatThrottle(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &keyCountModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf), WithKeyRepeatThrottle(time.Second))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	for range 5 {
+		p.SendSync(KeyMsg{Type: KeyDown})
+	}
+	p.SendSync(KeyMsg{Type: KeyUp})
+	p.SendSync(KeyMsg{Type: KeyDown})
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	// Repeated keys are dropped, distinct keys aren't.
+	if want := []string{"down", "up", "down"}; !reflect.DeepEqual(m.keys, want) {
+		t.Fatalf("expected keys %v, got %v", want, m.keys)
+	}
+}
+
+func TestTeaErrors(t *testing.T) {
+	var buf bytes.Buffer
+	in := bytes.NewBufferString("\x1b[?999za")
+
+	p := NewProgram(&testModel{}, WithInput(in), WithOutput(&buf))
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-p.Errors():
+		if !errors.Is(err, ErrUnknownInput) {
+			t.Fatalf("expected ErrUnknownInput, got %v", err)
+		}
+	default:
+		t.Fatal("expected an error for the unknown input")
+	}
+}
+
+func TestTeaErrorsDropOldest(t *testing.T) {
+	p := NewProgram(nil)
+	for i := range errorsBufferSize + 1 {
+		p.reportError(fmt.Errorf("error %d", i))
+	}
+
+	if err := <-p.Errors(); err.Error() != "error 1" {
+		t.Fatalf("expected the oldest error to be dropped, got %v", err)
+	}
+}
+
+func TestTeaOutput(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewProgram(&testModel{}, WithOutput(&buf))
+	if p.Output() != &buf {
+		t.Fatalf("expected the output passed to WithOutput, got %v", p.Output())
+	}
+
+	if p := NewProgram(&testModel{}); p.Output() != os.Stdout {
+		t.Fatalf("expected stdout by default, got %v", p.Output())
+	}
+}
+
+func TestTeaProgramContext(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+
+	done := make(chan struct{})
+	go func() {
+		<-p.Context().Done()
+		close(done)
+	}()
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+	waitForModelExecution(t, m)
+
+	select {
+	case <-done:
+		t.Fatal("expected the context to be alive while the program runs")
+	default:
+	}
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the context to be cancelled after the program quit")
+	}
+}
+
+type windowSizeTestModel struct {
+	unpause chan struct{}
+	sizes   []WindowSizeMsg
+}
+
+type pauseMsg struct{}
+
+func (m *windowSizeTestModel) Init() Cmd { return nil }
+
+func (m *windowSizeTestModel) Update(msg Msg) (Model, Cmd) {
+	switch msg := msg.(type) {
+	case pauseMsg:
+		<-m.unpause
+	case WindowSizeMsg:
+		m.sizes = append(m.sizes, msg)
+	}
+	return m, nil
+}
+
+func (m *windowSizeTestModel) View() string { return "" }
+
+func TestTeaLatestWindowSizeOnly(t *testing.T) {
+	var in bytes.Buffer
+
+	m := &windowSizeTestModel{unpause: make(chan struct{})}
+	p := NewProgram(m, WithInput(&in), WithoutRenderer(), WithLatestWindowSizeOnly())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	// Pause the event loop while the sizes are enqueued.
+	p.Send(pauseMsg{})
+	go p.Send(WindowSizeMsg{Width: 80, Height: 24})
+	for {
+		p.pendingSizeMtx.Lock()
+		pending := p.pendingSize != nil
+		p.pendingSizeMtx.Unlock()
+		if pending {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	p.Send(WindowSizeMsg{Width: 100, Height: 30})
+	p.Send(WindowSizeMsg{Width: 120, Height: 40})
+	close(m.unpause)
+
+	p.SendSync(nil)
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []WindowSizeMsg{{Width: 120, Height: 40}}; !reflect.DeepEqual(m.sizes, want) {
+		t.Fatalf("expected only the latest size %v, got %v", want, m.sizes)
+	}
+}
+
+func TestTeaAltScreenActive(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+	if p.AltScreenActive() {
+		t.Fatal("expected alt screen to be inactive before the program starts")
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+	waitForModelExecution(t, m)
+
+	p.SendSync(EnterAltScreen())
+	if !p.AltScreenActive() {
+		t.Fatal("expected alt screen to be active")
+	}
+
+	p.SendSync(ExitAltScreen())
+	if p.AltScreenActive() {
+		t.Fatal("expected alt screen to be inactive")
+	}
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTeaAltScreenActiveWhileStarting(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	// Query the state while Run sets up the renderer.
+	for i := 0; i < 100; i++ {
+		if p.AltScreenActive() {
+			t.Fatal("expected alt screen to be inactive")
+		}
+	}
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTeaAltScreenActiveWithoutRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf), WithoutRenderer())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	p.SendSync(EnterAltScreen())
+	if p.AltScreenActive() {
+		t.Fatal("expected alt screen to be inactive without a renderer")
+	}
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTeaTrySendDroppedMessages(t *testing.T) {
+	var dropped []Msg
+	p := NewProgram(nil, WithoutRenderer(), WithDroppedMessageHook(func(msg Msg) {
+		dropped = append(dropped, msg)
+	}))
+	defer p.cancel()
+	p.msgs = make(chan Msg, 2)
+
+	for i := 0; i < 5; i++ {
+		ok := p.TrySend(i)
+		if want := i < 2; ok != want {
+			t.Fatalf("TrySend(%d) = %v, want %v", i, ok, want)
+		}
+	}
+
+	if got := p.DroppedMessages(); got != 3 {
+		t.Fatalf("expected 3 dropped messages, got %d", got)
+	}
+	if fmt.Sprint(dropped) != "[2 3 4]" {
+		t.Fatalf("expected the hook to receive the dropped messages, got %v", dropped)
+	}
+
+	// Sending to a terminated program is a no-op, not a drop.
+	p.cancel()
+	if p.TrySend(5) {
+		t.Fatal("expected TrySend to fail after the program terminated")
+	}
+	if got := p.DroppedMessages(); got != 3 {
+		t.Fatalf("expected dropped messages to remain at 3, got %d", got)
+	}
+}
+
 func TestTeaNoRun(t *testing.T) {
 	var buf bytes.Buffer
 	var in bytes.Buffer
@@ -534,6 +1143,62 @@ func TestTeaPanic(t *testing.T) {
 	}
 }
 
+type panicViewModel struct {
+	testModel
+	panicking atomic.Bool
+}
+
+func (m *panicViewModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(panicMsg); ok {
+		m.panicking.Store(true)
+		return m, nil
+	}
+	_, cmd := m.testModel.Update(msg)
+	return m, cmd
+}
+
+func (m *panicViewModel) View() string {
+	if m.panicking.Load() {
+		panic("testing view panic behavior")
+	}
+	return m.testModel.View()
+}
+
+func TestTeaViewPanic(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &panicViewModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+	go func() {
+		for {
+			time.Sleep(time.Millisecond)
+			if m.executed.Load() != nil {
+				p.Send(panicMsg{})
+				return
+			}
+		}
+	}()
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, ErrProgramPanic) {
+			t.Fatalf("Expected %v, got %v", ErrProgramPanic, err)
+		}
+		if !errors.Is(err, ErrProgramKilled) {
+			t.Fatalf("Expected %v, got %v", ErrProgramKilled, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("program hung after View panicked")
+	}
+}
+
 func TestTeaGoroutinePanic(t *testing.T) {
 	var buf bytes.Buffer
 	var in bytes.Buffer
@@ -680,6 +1345,51 @@ func TestProgramPrintln(t *testing.T) {
 	}
 }
 
+func TestProgramPrintBlock(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+	errChan := make(chan error, 1)
+
+	go func() {
+		_, err := p.Run()
+		errChan <- err
+	}()
+
+	waitForModelExecution(t, m)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			p.Println("other")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		p.PrintBlock([]string{"block-one", "block-two", "block-three"})
+	}()
+	wg.Wait()
+	time.Sleep(25 * time.Millisecond)
+	p.Quit()
+
+	err := <-errChan
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "block-one\r\nblock-two\r\nblock-three") {
+		t.Fatalf("expected block lines to be printed contiguously, got %q", out)
+	}
+	if n := strings.Count(out, "other"); n != 10 {
+		t.Fatalf("expected 10 other lines, got %d in %q", n, out)
+	}
+}
+
 func TestProgramPrintf(t *testing.T) {
 	var buf bytes.Buffer
 	var in bytes.Buffer
@@ -1360,3 +2070,504 @@ func TestPrintfFormattingVariants(t *testing.T) {
 		assertPrintfResult(t, "iface ref slice plus: %+v", []interface{}{value}, expected)
 	})
 }
+
+// frameRecorder is a renderer that records every frame written to it.
+type frameRecorder struct {
+	nilRenderer
+	mtx    sync.Mutex
+	frames []string
+}
+
+func (r *frameRecorder) write(s string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.frames = append(r.frames, s)
+}
+
+func (r *frameRecorder) recorded() []string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]string(nil), r.frames...)
+}
+
+type readyTestModel struct {
+	testModel
+}
+
+func (m *readyTestModel) Init() Cmd {
+	return func() Msg { return incrementMsg{} }
+}
+
+func (m *readyTestModel) Update(msg Msg) (Model, Cmd) {
+	m.testModel.Update(msg)
+	if _, ok := msg.(incrementMsg); ok {
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *readyTestModel) Ready() bool {
+	return m.counter.Load() != nil
+}
+
+func TestTeaModelReady(t *testing.T) {
+	for _, loadingView := range []string{"", "loading..."} {
+		t.Run(fmt.Sprintf("loading view %q", loadingView), func(t *testing.T) {
+			var in bytes.Buffer
+			r := &frameRecorder{}
+			p := NewProgram(&readyTestModel{}, WithInput(&in), WithLoadingView(loadingView))
+			p.renderer = r
+			if _, err := p.Run(); err != nil {
+				t.Fatal(err)
+			}
+
+			frames := r.recorded()
+			if len(frames) == 0 {
+				t.Fatal("no frames rendered")
+			}
+			if frames[len(frames)-1] != "success\n" {
+				t.Fatalf("expected the model's view once ready, got %q", frames)
+			}
+			first := frames[0]
+			if loadingView == "" {
+				if first != "success\n" {
+					t.Fatalf("expected nothing to render before the model is ready, got %q", frames)
+				}
+				return
+			}
+			if first != loadingView {
+				t.Fatalf("expected the loading view to render first, got %q", frames)
+			}
+		})
+	}
+}
+
+type syncInitTestModel struct {
+	count int
+}
+
+func (m *syncInitTestModel) Init() Cmd {
+	inc := func() Msg { return incrementMsg{} }
+	slow := func() Msg {
+		time.Sleep(100 * time.Millisecond)
+		return incrementMsg{}
+	}
+	return Batch(inc, Sequence(inc, inc), slow)
+}
+
+func (m *syncInitTestModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(incrementMsg); ok {
+		m.count++
+		if m.count == 4 {
+			return m, Quit
+		}
+	}
+	return m, nil
+}
+
+func (m *syncInitTestModel) View() string {
+	return fmt.Sprintf("count %d\n", m.count)
+}
+
+func TestTeaSynchronousInit(t *testing.T) {
+	original := synchronousInitTimeout
+	synchronousInitTimeout = 20 * time.Millisecond
+	t.Cleanup(func() { synchronousInitTimeout = original })
+
+	var in bytes.Buffer
+	r := &frameRecorder{}
+	p := NewProgram(&syncInitTestModel{}, WithInput(&in), WithSynchronousInit())
+	p.renderer = r
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	frames := r.recorded()
+	if len(frames) == 0 || frames[0] != "count 3\n" {
+		t.Fatalf("expected the first frame to reflect Init's messages, got %q", frames)
+	}
+	// The slow command didn't hold up the first frame, but still ran.
+	if last := frames[len(frames)-1]; last != "count 4\n" {
+		t.Fatalf("expected the slow command's message to be delivered, got %q", frames)
+	}
+}
+
+// disconnectingWriter fails with EPIPE once disconnected.
+type disconnectingWriter struct {
+	disconnected atomic.Bool
+}
+
+func (w *disconnectingWriter) Write(b []byte) (int, error) {
+	if w.disconnected.Load() {
+		return 0, syscall.EPIPE
+	}
+	return len(b), nil
+}
+
+func TestTeaTerminalDisconnected(t *testing.T) {
+	var in bytes.Buffer
+	out := &disconnectingWriter{}
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(out))
+	var stopped atomic.Bool
+	p.OnStop(func() { stopped.Store(true) })
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+	waitForModelExecution(t, m)
+
+	out.disconnected.Store(true)
+	p.Send(ClearScreen())
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, ErrTerminalDisconnected) || !errors.Is(err, ErrProgramKilled) || !errors.Is(err, syscall.EPIPE) {
+			t.Fatalf("expected the program to stop with ErrTerminalDisconnected, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the program to stop once the terminal disconnected")
+	}
+	if !stopped.Load() {
+		t.Fatal("expected the OnStop hook to run")
+	}
+}
+
+// countingWriter counts the writes made to it.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(b)
+}
+
+func TestTeaOutputBuffer(t *testing.T) {
+	run := func(opts ...ProgramOption) *countingWriter {
+		t.Helper()
+		var in bytes.Buffer
+		out := &countingWriter{}
+		p := NewProgram(&testModel{}, append([]ProgramOption{WithInput(&in), WithOutput(out)}, opts...)...)
+		go p.Send(sequenceMsg{
+			func() Msg { return WindowSizeMsg{Width: 80, Height: 24} },
+			HideCursor, ShowCursor, EnableMouseCellMotion, DisableMouse,
+			Println("printed"),
+			Quit,
+		})
+		if _, err := p.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	unbuffered := run()
+	buffered := run(WithOutputBuffer(4096))
+	if buffered.writes >= unbuffered.writes {
+		t.Fatalf("expected fewer writes with an output buffer, got %d, and %d without", buffered.writes, unbuffered.writes)
+	}
+	for _, want := range []string{"printed", "success"} {
+		if !strings.Contains(buffered.String(), want) {
+			t.Fatalf("expected %q in the output, got %q", want, buffered.String())
+		}
+	}
+	// The teardown sequences are written before the program exits.
+	if teardown := "\x1b[?2004l\x1b[?25h\x1b[?1002l\x1b[?1003l\x1b[?1006l"; !strings.HasSuffix(buffered.String(), teardown) {
+		t.Fatalf("expected the output to end with the teardown sequences, got %q", buffered.String())
+	}
+}
+
+// slowWriter takes its time writing.
+type slowWriter struct {
+	mtx   sync.Mutex
+	delay time.Duration
+	buf   bytes.Buffer
+}
+
+func (w *slowWriter) Write(b []byte) (int, error) {
+	time.Sleep(w.delay)
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.buf.Write(b)
+}
+
+func (w *slowWriter) String() string {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.buf.String()
+}
+
+type slowOutputTestModel struct {
+	slow bool
+}
+
+func (m *slowOutputTestModel) Init() Cmd { return nil }
+
+func (m *slowOutputTestModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(SlowOutputMsg); ok {
+		m.slow = true
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *slowOutputTestModel) View() string { return "slow frame\n" }
+
+func TestTeaSlowOutput(t *testing.T) {
+	var in bytes.Buffer
+	out := &slowWriter{delay: 50 * time.Millisecond}
+	m := &slowOutputTestModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(out), WithSlowOutputThreshold(10*time.Millisecond))
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the program to quit on a SlowOutputMsg")
+	}
+	if !m.slow {
+		t.Fatal("expected a SlowOutputMsg")
+	}
+	if !strings.Contains(out.String(), "slow frame") {
+		t.Fatalf("expected the frame to be written, got %q", out.String())
+	}
+
+	// Fast outputs aren't reported.
+	var fast bytes.Buffer
+	m = &slowOutputTestModel{}
+	p = NewProgram(m, WithInput(&in), WithOutput(&fast), WithSlowOutputThreshold(100*time.Millisecond))
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		p.Quit()
+	}()
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if m.slow {
+		t.Fatal("expected no SlowOutputMsg for a fast output")
+	}
+}
+
+func TestTeaStartupBanner(t *testing.T) {
+	run := func(opts ...ProgramOption) string {
+		t.Helper()
+		var in, out bytes.Buffer
+		m := &testModel{}
+		banner := WithStartupBanner(func() string { return "myapp v1.2.3" })
+		p := NewProgram(m, append([]ProgramOption{WithInput(&in), WithOutput(&out), banner}, opts...)...)
+		go func() {
+			waitForModelExecution(t, m)
+			p.Quit()
+		}()
+		if _, err := p.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	out := run()
+	banner, view := strings.Index(out, "myapp v1.2.3"), strings.Index(out, "success")
+	if banner < 0 || view < 0 || banner > view {
+		t.Fatalf("expected the banner before the first view, got %q", out)
+	}
+
+	if out := run(WithAltScreen()); strings.Contains(out, "myapp") {
+		t.Fatalf("expected no banner in the alt screen, got %q", out)
+	}
+}
+
+type nilModelMsg struct{}
+
+type nilModelTestModel struct {
+	updates int
+}
+
+func (m *nilModelTestModel) Init() Cmd { return nil }
+
+func (m *nilModelTestModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(nilModelMsg); ok {
+		return nil, nil
+	}
+	if _, ok := msg.(incrementMsg); ok {
+		m.updates++
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *nilModelTestModel) View() string { return "" }
+
+func TestTeaNilModel(t *testing.T) {
+	t.Run("lenient", func(t *testing.T) {
+		var in, out bytes.Buffer
+		m := &nilModelTestModel{}
+		p := NewProgram(m, WithInput(&in), WithOutput(&out))
+		go p.Send(sequenceMsg{
+			func() Msg { return nilModelMsg{} },
+			func() Msg { return incrementMsg{} },
+		})
+		model, err := p.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if model != m || m.updates != 1 {
+			t.Fatalf("expected the previous model to be kept, got %#v", model)
+		}
+		select {
+		case err := <-p.Errors():
+			if !errors.Is(err, ErrNilModel) {
+				t.Fatalf("expected ErrNilModel, got %v", err)
+			}
+		default:
+			t.Fatal("expected ErrNilModel to be reported")
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		var in, out bytes.Buffer
+		m := &nilModelTestModel{}
+		p := NewProgram(m, WithInput(&in), WithOutput(&out), WithStrictModel())
+		go p.Send(nilModelMsg{})
+		model, err := p.Run()
+		if !errors.Is(err, ErrNilModel) {
+			t.Fatalf("expected ErrNilModel, got %v", err)
+		}
+		if model != m {
+			t.Fatalf("expected the previous model, got %#v", model)
+		}
+	})
+}
+
+func TestTeaRunCmd(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	increment := func() Msg { return incrementMsg{} }
+	p.RunCmd(Batch(increment, increment))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for m.counter.Load() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the commands to increment the counter twice, got %v", m.counter.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	// Running commands after the program has exited is a no-op.
+	p.RunCmd(increment)
+	if got := m.counter.Load(); got != 2 {
+		t.Fatalf("expected the counter to stay at 2, got %v", got)
+	}
+}
+
+func TestRecordMessages(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	p := NewProgram(&testModel{}, WithInput(&in), WithOutput(&buf), WithFilter(func(_ Model, msg Msg) Msg {
+		if _, ok := msg.(panicMsg); ok {
+			return nil
+		}
+		return msg
+	}))
+	msgs := recordMessages(p)
+
+	increment := func() Msg { return incrementMsg{} }
+	go p.Send(sequenceMsg{
+		func() Msg { return panicMsg{} },
+		Batch(increment, increment),
+		// The filter sees the stream and QuitMsg, Update doesn't.
+		StreamCmd(func(emit func(Msg)) error {
+			emit(incrementMsg{})
+			emit(QuitMsg{})
+			return nil
+		}),
+	})
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Msg{incrementMsg{}, incrementMsg{}, incrementMsg{}}
+	if !reflect.DeepEqual(*msgs, want) {
+		t.Fatalf("expected the messages %#v, got %#v", want, *msgs)
+	}
+}
+
+func TestTeaInitialCmds(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	// testModel's Init returns nil, so everything the program receives at
+	// startup comes from the initial commands.
+	m := &testModel{}
+	increment := func() Msg { return incrementMsg{} }
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf), WithInitialCmds(Sequence(increment, Quit), nil))
+	msgs := recordMessages(p)
+
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := m.counter.Load(); got != 1 {
+		t.Fatalf("expected the initial command to increment the counter once, got %v", got)
+	}
+	want := []Msg{incrementMsg{}}
+	if !reflect.DeepEqual(*msgs, want) {
+		t.Fatalf("expected the messages %#v, got %#v", want, *msgs)
+	}
+}
+
+func TestTeaUptime(t *testing.T) {
+	var buf bytes.Buffer
+	var in bytes.Buffer
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(&in), WithOutput(&buf))
+	if uptime := p.Uptime(); uptime != 0 {
+		t.Fatalf("expected no uptime before running, got %v", uptime)
+	}
+
+	go func() {
+		waitForModelExecution(t, m)
+		time.Sleep(10 * time.Millisecond)
+		p.Quit()
+	}()
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	uptime := p.Uptime()
+	if uptime < 10*time.Millisecond {
+		t.Fatalf("expected the program to have run at least 10ms, got %v", uptime)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if again := p.Uptime(); again != uptime {
+		t.Fatalf("expected the uptime to stay %v after the program stopped, got %v", uptime, again)
+	}
+	if stats := p.RenderStats(); stats.Uptime != uptime {
+		t.Fatalf("expected the render stats to report the uptime %v, got %v", uptime, stats.Uptime)
+	}
+}
@@ -0,0 +1,63 @@
+// Package teatest provides helpers to test Bubble Tea programs and
+// components.
+package teatest
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbletea/internal/sgr"
+)
+
+// modes are the terminal modes a program may change, keyed by the private
+// mode number, with the sequence that sets the mode back to the terminal's
+// default.
+var modes = map[string]struct {
+	name    string
+	restore string
+}{
+	"7":    {"line wrapping", "h"},
+	"25":   {"cursor visibility", "h"},
+	"1000": {"mouse tracking", "l"},
+	"1002": {"mouse cell motion", "l"},
+	"1003": {"mouse all motion", "l"},
+	"1004": {"focus reporting", "l"},
+	"1006": {"SGR mouse mode", "l"},
+	"1049": {"alternate screen", "l"},
+	"2004": {"bracketed paste", "l"},
+}
+
+var modeRe = regexp.MustCompile(`\x1b\[\?([0-9;]+)([hl])`)
+
+// AssertCleanExit fails the test if the output captured from a program leaves
+// the terminal dirty: any terminal mode changed by the program, such as the
+// alternate screen, mouse tracking, bracketed paste, focus reporting or a
+// hidden cursor, has to be set back, and text attributes have to be reset.
+//
+//	var out bytes.Buffer
+//	p := tea.NewProgram(model{}, tea.WithInput(nil), tea.WithOutput(&out))
+//	if _, err := p.Run(); err != nil {
+//		t.Fatal(err)
+//	}
+//	teatest.AssertCleanExit(t, out.String())
+func AssertCleanExit(t testing.TB, output string) {
+	t.Helper()
+
+	// The last state each mode was set to.
+	state := make(map[string]string)
+	for _, m := range modeRe.FindAllStringSubmatch(output, -1) {
+		for _, mode := range strings.Split(m[1], ";") {
+			state[mode] = m[2]
+		}
+	}
+	for mode, s := range state {
+		if want, ok := modes[mode]; ok && s != want.restore {
+			t.Errorf("terminal left dirty: %s (mode %s) not restored", want.name, mode)
+		}
+	}
+
+	if sgr.Open(output) {
+		t.Errorf("terminal left dirty: text attributes not reset")
+	}
+}
@@ -0,0 +1,63 @@
+package teatest
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type model string
+
+func (m model) Init() tea.Cmd { return tea.Quit }
+
+func (m model) Update(tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
+
+func (m model) View() string { return string(m) }
+
+// recorder records the errors reported by AssertCleanExit.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertCleanExit(t *testing.T) {
+	var in, out bytes.Buffer
+	p := tea.NewProgram(model("success\n"), tea.WithInput(&in), tea.WithOutput(&out),
+		tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithReportFocus())
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	AssertCleanExit(t, out.String())
+}
+
+func TestAssertCleanExitDirty(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		errors int
+	}{
+		{name: "open sgr", output: "\x1b[31mred", errors: 1},
+		{name: "color with zero", output: "\x1b[38;5;0mblack", errors: 1},
+		{name: "hidden cursor", output: "\x1b[?25lview", errors: 1},
+		{name: "modes left on", output: "\x1b[?2004h\x1b[?1002;1006hview", errors: 3},
+		{name: "restored", output: "\x1b[?1049h\x1b[1mbold\x1b[0m\x1b[?1049l", errors: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			AssertCleanExit(r, test.output)
+			if len(r.errors) != test.errors {
+				t.Fatalf("expected %d errors, got %q", test.errors, r.errors)
+			}
+		})
+	}
+}
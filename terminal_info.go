@@ -0,0 +1,105 @@
+package tea
+
+import (
+	"strings"
+	"time"
+)
+
+// terminalInfoTimeout is how long TerminalInfo waits for the terminal to
+// respond before giving up.
+var terminalInfoTimeout = 2 * time.Second //nolint:mnd
+
+// requestTerminalVersion is the XTVERSION query, which asks the terminal to
+// report its name and version.
+const requestTerminalVersion = "\x1b[>0q"
+
+// TerminalInfo is a command that queries the terminal for its name and version
+// using the XTVERSION escape sequence. The parsed values are passed to fn and
+// the resulting message is delivered to Update. This can be useful to work
+// around terminal specific quirks.
+//
+// If the terminal doesn't respond in a timely manner, which is the case for
+// terminals that don't support the query, fn is called with empty strings.
+//
+//	type terminalMsg struct{ name, version string }
+//
+//	func (m model) Init() Cmd {
+//	    return tea.TerminalInfo(func(name, version string) tea.Msg {
+//	        return terminalMsg{name, version}
+//	    })
+//	}
+func TerminalInfo(fn func(name, version string) Msg) Cmd {
+	return func() Msg {
+		return terminalInfoRequestMsg{fn: fn}
+	}
+}
+
+// terminalInfoRequestMsg is an internal message that queries the terminal for
+// its name and version. You can send a terminalInfoRequestMsg with
+// TerminalInfo.
+type terminalInfoRequestMsg struct {
+	fn func(name, version string) Msg
+}
+
+// terminalInfoTimeoutMsg is an internal message that signals a pending
+// TerminalInfo request timed out.
+type terminalInfoTimeoutMsg struct {
+	req *terminalInfoRequest
+}
+
+// terminalVersionMsg is reported by the input reader when the terminal
+// responds to an XTVERSION query.
+type terminalVersionMsg struct {
+	name    string
+	version string
+}
+
+// terminalInfoRequest is a TerminalInfo request awaiting a response.
+type terminalInfoRequest struct {
+	fn func(name, version string) Msg
+}
+
+// requestTerminalInfo registers a pending TerminalInfo request and schedules
+// its timeout. The query itself is written by the renderer.
+func (p *Program) requestTerminalInfo(fn func(name, version string) Msg) {
+	req := &terminalInfoRequest{fn: fn}
+	p.terminalInfoRequests = append(p.terminalInfoRequests, req)
+
+	timeout := time.After(terminalInfoTimeout)
+	go func() {
+		select {
+		case <-p.ctx.Done():
+		case <-timeout:
+			p.Send(terminalInfoTimeoutMsg{req: req})
+		}
+	}()
+}
+
+// resolveTerminalInfo delivers the given name and version to the pending
+// TerminalInfo requests. If req is not nil only that request is resolved.
+func (p *Program) resolveTerminalInfo(req *terminalInfoRequest, name, version string) {
+	pending := p.terminalInfoRequests[:0]
+	for _, r := range p.terminalInfoRequests {
+		if req != nil && r != req {
+			pending = append(pending, r)
+			continue
+		}
+		if r.fn != nil {
+			msg := r.fn(name, version)
+			go p.Send(msg)
+		}
+	}
+	p.terminalInfoRequests = pending
+}
+
+// parseTerminalVersion splits an XTVERSION report into a name and version.
+// Terminals report either "name version" or "name(version)".
+func parseTerminalVersion(s string) (name, version string) {
+	if name, version, ok := strings.Cut(s, " "); ok {
+		return name, strings.TrimSpace(version)
+	}
+	if name, version, ok := strings.Cut(s, "("); ok {
+		return name, strings.TrimSuffix(version, ")")
+	}
+	return s, ""
+}
@@ -0,0 +1,174 @@
+package tea
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type terminalInfoTestMsg struct {
+	name    string
+	version string
+}
+
+type terminalInfoTestModel struct {
+	info terminalInfoTestMsg
+}
+
+func (m *terminalInfoTestModel) Init() Cmd {
+	return TerminalInfo(func(name, version string) Msg {
+		return terminalInfoTestMsg{name: name, version: version}
+	})
+}
+
+func (m *terminalInfoTestModel) Update(msg Msg) (Model, Cmd) {
+	switch msg := msg.(type) {
+	case terminalInfoTestMsg:
+		m.info = msg
+		return m, Quit
+	case KeyMsg:
+		panic("terminal version report leaked into the key stream: " + msg.String())
+	}
+	return m, nil
+}
+
+func (m *terminalInfoTestModel) View() string {
+	return "info"
+}
+
+// queryResponder answers terminal queries written to it by writing the
+// response to the program's input.
+type queryResponder struct {
+	mtx      sync.Mutex
+	buf      bytes.Buffer
+	query    string
+	response string
+	input    io.Writer
+	answered bool
+}
+
+func (w *queryResponder) Write(b []byte) (int, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	n, err := w.buf.Write(b)
+	if !w.answered && strings.Contains(w.buf.String(), w.query) {
+		w.answered = true
+		go io.WriteString(w.input, w.response) //nolint:errcheck
+	}
+	return n, err
+}
+
+func TestDetectTerminalVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		width   int
+		msg     Msg
+		hasMore bool
+		more    bool // whether more data can follow the input
+	}{
+		{
+			name:  "space separated",
+			input: "\x1bP>|WezTerm 20230712-072601\x1b\\",
+			width: 29,
+			msg:   terminalVersionMsg{name: "WezTerm", version: "20230712-072601"},
+		},
+		{
+			name:  "parenthesized",
+			input: "\x1bP>|XTerm(370)\x1b\\x",
+			width: 16,
+			msg:   terminalVersionMsg{name: "XTerm", version: "370"},
+		},
+		{
+			name:  "bel terminated",
+			input: "\x1bP>|tmux 3.4\a",
+			width: 13,
+			msg:   terminalVersionMsg{name: "tmux", version: "3.4"},
+		},
+		{
+			name:    "incomplete",
+			input:   "\x1bP>|kitty(0.3",
+			hasMore: true,
+		},
+		{
+			name:    "split start",
+			input:   "\x1bP>",
+			hasMore: true,
+			more:    true,
+		},
+		{
+			name:  "alt key without more data",
+			input: "\x1bP>",
+			width: 2,
+			msg:   KeyMsg{Type: KeyRunes, Runes: []rune{'P'}, Alt: true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, msg := detectOneMsg([]byte(test.input), test.more)
+			if test.hasMore {
+				if w != 0 {
+					t.Fatalf("expected a short read, got width %d and %#v", w, msg)
+				}
+				return
+			}
+			if w != test.width {
+				t.Errorf("expected width %d, got %d", test.width, w)
+			}
+			if !reflect.DeepEqual(msg, test.msg) {
+				t.Errorf("expected %#v, got %#v", test.msg, msg)
+			}
+		})
+	}
+}
+
+func TestTerminalInfo(t *testing.T) {
+	inR, inW := io.Pipe()
+	defer inW.Close() //nolint:errcheck
+
+	out := &queryResponder{
+		query:    requestTerminalVersion,
+		response: "\x1bP>|WezTerm 20240203-110809\x1b\\",
+		input:    inW,
+	}
+
+	m := &terminalInfoTestModel{}
+	p := NewProgram(m, WithInput(inR), WithOutput(out))
+	go func() {
+		time.Sleep(3 * time.Second)
+		p.Kill()
+	}()
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := terminalInfoTestMsg{name: "WezTerm", version: "20240203-110809"}
+	if m.info != want {
+		t.Fatalf("expected %#v, got %#v", want, m.info)
+	}
+}
+
+func TestTerminalInfoTimeout(t *testing.T) {
+	original := terminalInfoTimeout
+	terminalInfoTimeout = 10 * time.Millisecond
+	t.Cleanup(func() { terminalInfoTimeout = original })
+
+	var buf bytes.Buffer
+	m := &terminalInfoTestModel{info: terminalInfoTestMsg{name: "unset"}}
+	p := NewProgram(m, WithInput(nil), WithOutput(&buf))
+	go func() {
+		time.Sleep(3 * time.Second)
+		p.Kill()
+	}()
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.info != (terminalInfoTestMsg{}) {
+		t.Fatalf("expected empty name and version on timeout, got %#v", m.info)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"time"
 
 	"github.com/charmbracelet/x/term"
@@ -48,7 +49,7 @@ func (p *Program) restoreTerminalState() error {
 			p.renderer.disableReportFocus()
 		}
 
-		if p.renderer.altScreen() {
+		if p.renderer.altScreen() && !p.keepAltScreen {
 			p.renderer.exitAltScreen()
 
 			// give the terminal a moment to catch up
@@ -59,13 +60,49 @@ func (p *Program) restoreTerminalState() error {
 	return p.restoreInput()
 }
 
-// restoreInput restores the tty input to its original state.
-func (p *Program) restoreInput() error {
-	if p.ttyInput != nil && p.previousTtyInputState != nil {
-		if err := term.Restore(p.ttyInput.Fd(), p.previousTtyInputState); err != nil {
+// enterRawMode puts the tty input into raw mode, unless it already is.
+func (p *Program) enterRawMode() error {
+	p.rawModeMtx.Lock()
+	defer p.rawModeMtx.Unlock()
+
+	if p.ttyInput == nil || p.previousTtyInputState != nil {
+		return nil
+	}
+	// In a session, the terminal may still be in raw mode from the previous
+	// program.
+	if p.session != nil {
+		if state := p.session.takeRawState(p.ttyInput); state != nil {
+			p.previousTtyInputState = state
+			return nil
+		}
+	}
+	state, err := p.makeInputRaw()
+	// Keep the previous state even on errors, so it's restored on exit.
+	p.previousTtyInputState = state
+	return err
+}
+
+// exitRawMode restores the tty input to the state it had before entering raw
+// mode.
+func (p *Program) exitRawMode() error {
+	p.rawModeMtx.Lock()
+	state := p.previousTtyInputState
+	p.previousTtyInputState = nil
+	p.rawModeMtx.Unlock()
+
+	if p.ttyInput != nil && state != nil {
+		if err := term.Restore(p.ttyInput.Fd(), state); err != nil {
 			return fmt.Errorf("error restoring console: %w", err)
 		}
 	}
+	return nil
+}
+
+// restoreInput restores the tty input to its original state.
+func (p *Program) restoreInput() error {
+	if err := p.exitRawMode(); err != nil {
+		return err
+	}
 	if p.ttyOutput != nil && p.previousOutputState != nil {
 		if err := term.Restore(p.ttyOutput.Fd(), p.previousOutputState); err != nil {
 			return fmt.Errorf("error restoring console: %w", err)
@@ -74,6 +111,74 @@ func (p *Program) restoreInput() error {
 	return nil
 }
 
+// RunInCookedMode takes the terminal out of raw mode, calls fn and puts the
+// terminal back into raw mode once fn returns, even if it panics. While fn
+// runs the program stops reading input, so fn can read from the terminal
+// with its normal line editing, for instance to ask for a password with
+// another library. Unlike ReleaseTerminal, the screen is left as it is.
+//
+// If the terminal isn't in raw mode, fn is simply called. RunInCookedMode
+// returns the error returned by fn or, failing that, any error switching
+// modes.
+func (p *Program) RunInCookedMode(fn func() error) (err error) {
+	p.rawModeMtx.Lock()
+	raw := p.previousTtyInputState != nil
+	p.rawModeMtx.Unlock()
+	if !raw {
+		return fn()
+	}
+
+	reading := p.cancelReader != nil
+	if reading {
+		p.cancelReader.Cancel()
+		p.waitForReadLoop()
+	}
+	if err := p.exitRawMode(); err != nil {
+		return err
+	}
+	defer func() {
+		rawErr := p.enterRawMode()
+		if reading {
+			if readErr := p.initCancelReader(false); rawErr == nil {
+				rawErr = readErr
+			}
+		}
+		if err == nil {
+			err = rawErr
+		}
+	}()
+
+	return fn()
+}
+
+// setInputEcho turns echoing the keys typed on the tty input on or off.
+func (p *Program) setInputEcho(on bool) {
+	if p.ttyInput == nil {
+		return
+	}
+	if err := setEcho(p.ttyInput.Fd(), on); err != nil {
+		return
+	}
+	p.inputEcho = on
+}
+
+// closeInputTTY closes a TTY opened by Run for input, unless the program's
+// session took it over to keep it in raw mode for the next program.
+func (p *Program) closeInputTTY(f term.File) {
+	if p.session != nil && p.session.own(f) {
+		return
+	}
+	_ = f.Close()
+}
+
+// startInputMsg is an internal message that signals the event loop to start
+// reading input, which is deferred with WithLazyRawMode.
+type startInputMsg struct{}
+
+func startInput() Msg {
+	return startInputMsg{}
+}
+
 // initCancelReader (re)commences reading inputs.
 func (p *Program) initCancelReader(cancel bool) error {
 	if cancel && p.cancelReader != nil {
@@ -81,10 +186,14 @@ func (p *Program) initCancelReader(cancel bool) error {
 		p.waitForReadLoop()
 	}
 
-	var err error
-	p.cancelReader, err = newInputReader(p.input, p.mouseMode)
-	if err != nil {
-		return fmt.Errorf("error creating cancelreader: %w", err)
+	if conn, ok := p.input.(net.Conn); ok && p.startupOptions.has(withConn) {
+		p.cancelReader = newConnReader(conn)
+	} else {
+		var err error
+		p.cancelReader, err = newInputReader(p.input, p.mouseMode)
+		if err != nil {
+			return fmt.Errorf("error creating cancelreader: %w", err)
+		}
 	}
 
 	p.readLoopDone = make(chan struct{})
@@ -96,7 +205,22 @@ func (p *Program) initCancelReader(cancel bool) error {
 func (p *Program) readLoop() {
 	defer close(p.readLoopDone)
 
-	err := readInputs(p.ctx, p.msgs, p.cancelReader)
+	// With lazy raw mode, enter raw mode right before reading.
+	if p.startupOptions.has(withLazyRawMode) {
+		if err := p.enterRawMode(); err != nil {
+			select {
+			case <-p.ctx.Done():
+			case p.errs <- err:
+			}
+			return
+		}
+	}
+
+	err := readInputs(p.ctx, p.msgs, p.cancelReader, p.inputInterceptor, p.escapeTimeout)
+	if _, ok := p.cancelReader.(*connReader); ok {
+		p.handleConnError(err)
+		return
+	}
 	if !errors.Is(err, io.EOF) && !errors.Is(err, cancelreader.ErrCanceled) {
 		select {
 		case <-p.ctx.Done():
@@ -119,12 +243,29 @@ func (p *Program) waitForReadLoop() {
 // checkResize detects the current size of the output and informs the program
 // via a WindowSizeMsg.
 func (p *Program) checkResize() {
+	p.checkSize(false)
+}
+
+// goCheckResize calls checkResize in the background. The check is waited on
+// before the program exits, so it doesn't send messages after shutdown.
+func (p *Program) goCheckResize() {
+	p.resizeChecks.Add(1)
+	go func() {
+		defer p.resizeChecks.Done()
+		p.checkResize()
+	}()
+}
+
+// checkSize detects the current size of the output and informs the program
+// via a WindowSizeMsg, followed by a WindowPixelSizeMsg if the size in pixels
+// is known. If initial is set, an InitialWindowSizeMsg is sent first.
+func (p *Program) checkSize(initial bool) {
 	if p.ttyOutput == nil {
 		// can't query window size
 		return
 	}
 
-	w, h, err := term.GetSize(p.ttyOutput.Fd())
+	w, h, pw, ph, err := getWindowSize(p.ttyOutput.Fd())
 	if err != nil {
 		select {
 		case <-p.ctx.Done():
@@ -134,8 +275,31 @@ func (p *Program) checkResize() {
 		return
 	}
 
+	// Programs rendering into a region only get to use that region.
+	if p.region != nil {
+		if w > 0 && h > 0 {
+			pw, ph = pw*p.region.width/w, ph*p.region.height/h
+		}
+		w, h = p.region.width, p.region.height
+	}
+
+	if initial {
+		p.Send(InitialWindowSizeMsg{
+			Width:  w,
+			Height: h,
+		})
+	}
+
 	p.Send(WindowSizeMsg{
 		Width:  w,
 		Height: h,
 	})
+	if pw > 0 && ph > 0 {
+		p.Send(WindowPixelSizeMsg{
+			Width:       w,
+			Height:      h,
+			PixelWidth:  pw,
+			PixelHeight: ph,
+		})
+	}
 }
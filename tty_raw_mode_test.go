@@ -42,8 +42,23 @@ type ttyHarness struct {
 	input        *fakeTTYInput
 	inputKind    ttyInputKind
 	rendererOn   bool
+	lazyRawMode  bool
 	openInputTTY func() (*fakeTTYInput, error)
 	cleanup      func()
+	session      *ttySession
+}
+
+// ttySession mirrors Session, holding the raw mode of its programs between
+// them.
+type ttySession struct {
+	cleanup func()
+}
+
+func (s *ttySession) close() {
+	if s.cleanup != nil {
+		s.cleanup()
+		s.cleanup = nil
+	}
 }
 
 func newTTYHarness() *ttyHarness {
@@ -83,12 +98,33 @@ func (h *ttyHarness) resolveInput() error {
 }
 
 func (h *ttyHarness) setupRawMode() error {
+	if h.lazyRawMode {
+		return nil
+	}
+	return h.enterRawMode()
+}
+
+// read mirrors the read loop, which enters raw mode before reading with lazy
+// raw mode.
+func (h *ttyHarness) read() error {
+	if !h.lazyRawMode || h.cleanup != nil {
+		return nil
+	}
+	return h.enterRawMode()
+}
+
+func (h *ttyHarness) enterRawMode() error {
 	if !h.rendererOn {
 		return nil
 	}
 	if h.input == nil || !h.input.isTTY {
 		return nil
 	}
+	if h.session != nil && h.session.cleanup != nil {
+		h.cleanup = h.session.cleanup
+		h.session.cleanup = nil
+		return nil
+	}
 	startRaw := h.input.isRaw
 	if err := h.input.setRawMode(true); err != nil {
 		return panicErr(err)
@@ -106,6 +142,28 @@ func (h *ttyHarness) restoreRawMode() {
 	}
 }
 
+// shutdown mirrors the end of Program.Run, which hands raw mode over to the
+// session, if any, instead of restoring it.
+func (h *ttyHarness) shutdown() {
+	if h.session != nil && h.session.cleanup == nil {
+		h.session.cleanup = h.cleanup
+		h.cleanup = nil
+	}
+	h.restoreRawMode()
+}
+
+// runInCookedMode mirrors Program.RunInCookedMode.
+func (h *ttyHarness) runInCookedMode(fn func() error) error {
+	if h.cleanup == nil {
+		return fn()
+	}
+	h.restoreRawMode()
+	defer func() {
+		_ = h.enterRawMode()
+	}()
+	return fn()
+}
+
 func panicErr(err error) error {
 	return errors.Join(ErrProgramPanic, err)
 }
@@ -149,6 +207,41 @@ func TestTTYRawModeRestoresInitialState(t *testing.T) {
 	}
 }
 
+func TestTTYLazyRawModeSkipsWithoutRead(t *testing.T) {
+	harness := newTTYHarness()
+	harness.lazyRawMode = true
+	harness.input = newFakeTTYInput(false)
+
+	if err := harness.setupRawMode(); err != nil {
+		t.Fatalf("setupRawMode() returned %v", err)
+	}
+	harness.restoreRawMode()
+
+	if len(harness.input.rawModeCalls) != 0 {
+		t.Fatalf("raw mode should not be entered before reading, got %v", harness.input.rawModeCalls)
+	}
+}
+
+func TestTTYLazyRawModeEntersOnRead(t *testing.T) {
+	harness := newTTYHarness()
+	harness.lazyRawMode = true
+	harness.input = newFakeTTYInput(false)
+
+	if err := harness.setupRawMode(); err != nil {
+		t.Fatalf("setupRawMode() returned %v", err)
+	}
+	for range 2 {
+		if err := harness.read(); err != nil {
+			t.Fatalf("read() returned %v", err)
+		}
+	}
+	harness.restoreRawMode()
+
+	if got, want := harness.input.rawModeCalls, []bool{true, false}; !slicesEqual(got, want) {
+		t.Fatalf("raw mode calls = %v, want %v", got, want)
+	}
+}
+
 func TestTTYRawModeSkipsNonTTYInputs(t *testing.T) {
 	harness := newTTYHarness()
 	harness.input = &fakeTTYInput{isTTY: false}
@@ -176,6 +269,99 @@ func TestTTYRawModeSkipsWhenRendererDisabled(t *testing.T) {
 	}
 }
 
+func TestTTYRunInCookedMode(t *testing.T) {
+	harness := newTTYHarness()
+	harness.input = newFakeTTYInput(false)
+	if err := harness.setupRawMode(); err != nil {
+		t.Fatalf("setupRawMode() returned %v", err)
+	}
+
+	expected := errors.New("boom")
+	err := harness.runInCookedMode(func() error {
+		if harness.input.isRaw {
+			t.Errorf("raw mode should be disabled while fn runs")
+		}
+		return expected
+	})
+	if !errors.Is(err, expected) {
+		t.Fatalf("expected the error returned by fn, got %v", err)
+	}
+	if !harness.input.isRaw {
+		t.Fatalf("raw mode should be restored after fn returns")
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("expected the panic to propagate")
+			}
+		}()
+		_ = harness.runInCookedMode(func() error {
+			panic("boom")
+		})
+	}()
+	if !harness.input.isRaw {
+		t.Fatalf("raw mode should be restored after fn panics")
+	}
+
+	harness.restoreRawMode()
+	if got, want := harness.input.rawModeCalls, []bool{true, false, true, false, true, false}; !slicesEqual(got, want) {
+		t.Fatalf("raw mode calls = %v, want %v", got, want)
+	}
+}
+
+func TestTTYRunInCookedModeWithoutRawMode(t *testing.T) {
+	harness := newTTYHarness()
+	harness.lazyRawMode = true
+	harness.input = newFakeTTYInput(false)
+	if err := harness.setupRawMode(); err != nil {
+		t.Fatalf("setupRawMode() returned %v", err)
+	}
+
+	called := false
+	if err := harness.runInCookedMode(func() error {
+		called = true
+		return nil
+	}); err != nil {
+		t.Fatalf("runInCookedMode() returned %v", err)
+	}
+	if !called {
+		t.Fatalf("expected fn to be called")
+	}
+	if len(harness.input.rawModeCalls) != 0 {
+		t.Fatalf("raw mode should not be toggled, got %v", harness.input.rawModeCalls)
+	}
+}
+
+func TestTTYSessionEntersRawModeOnce(t *testing.T) {
+	session := &ttySession{}
+	input := newFakeTTYInput(false)
+
+	for range 2 {
+		harness := newTTYHarness()
+		harness.input = input
+		harness.session = session
+		if err := harness.setupRawMode(); err != nil {
+			t.Fatalf("setupRawMode() returned %v", err)
+		}
+		if !input.isRaw {
+			t.Fatalf("raw mode should be enabled while the program runs")
+		}
+		harness.shutdown()
+	}
+	if got, want := input.rawModeCalls, []bool{true}; !slicesEqual(got, want) {
+		t.Fatalf("raw mode calls = %v, want %v", got, want)
+	}
+
+	session.close()
+	if got, want := input.rawModeCalls, []bool{true, false}; !slicesEqual(got, want) {
+		t.Fatalf("raw mode calls = %v, want %v", got, want)
+	}
+	if input.isRaw {
+		t.Fatalf("raw mode should be disabled after closing the session")
+	}
+}
+
 func TestTTYInputFallbackOpensNewTTY(t *testing.T) {
 	harness := newTTYHarness()
 	harness.input = &fakeTTYInput{isTTY: false}
@@ -10,15 +10,17 @@ import (
 	"syscall"
 
 	"github.com/charmbracelet/x/term"
+	"golang.org/x/sys/unix"
 )
 
 func (p *Program) initInput() (err error) {
 	// Check if input is a terminal
 	if f, ok := p.input.(term.File); ok && term.IsTerminal(f.Fd()) {
 		p.ttyInput = f
-		p.previousTtyInputState, err = term.MakeRaw(p.ttyInput.Fd())
-		if err != nil {
-			return fmt.Errorf("error entering raw mode: %w", err)
+		if !p.startupOptions.has(withLazyRawMode) {
+			if err := p.enterRawMode(); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -29,7 +31,16 @@ func (p *Program) initInput() (err error) {
 	return nil
 }
 
-func openInputTTY() (*os.File, error) {
+// makeInputRaw puts the tty input into raw mode, returning its previous state.
+func (p *Program) makeInputRaw() (*term.State, error) {
+	state, err := term.MakeRaw(p.ttyInput.Fd())
+	if err != nil {
+		return nil, fmt.Errorf("error entering raw mode: %w", err)
+	}
+	return state, nil
+}
+
+var openInputTTY = func() (*os.File, error) {
 	f, err := os.Open("/dev/tty")
 	if err != nil {
 		return nil, fmt.Errorf("could not open a new TTY: %w", err)
@@ -47,3 +58,31 @@ var suspendProcess = func() {
 	// blocks until a CONT happens...
 	<-c
 }
+
+// getWindowSize returns the size of the terminal in cells and, if known, in
+// pixels.
+func getWindowSize(fd uintptr) (width, height, pixelWidth, pixelHeight int, err error) {
+	ws, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("error getting window size: %w", err)
+	}
+	return int(ws.Col), int(ws.Row), int(ws.Xpixel), int(ws.Ypixel), nil
+}
+
+// setEcho sets the ECHO flag of the terminal, leaving the rest of its state,
+// such as raw mode, untouched.
+func setEcho(fd uintptr, on bool) error {
+	termios, err := unix.IoctlGetTermios(int(fd), ioctlReadTermios)
+	if err != nil {
+		return fmt.Errorf("error getting terminal state: %w", err)
+	}
+	if on {
+		termios.Lflag |= unix.ECHO
+	} else {
+		termios.Lflag &^= unix.ECHO
+	}
+	if err := unix.IoctlSetTermios(int(fd), ioctlWriteTermios, termios); err != nil {
+		return fmt.Errorf("error setting terminal state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,514 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || aix || zos
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris aix zos
+
+package tea
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/creack/pty"
+	"golang.org/x/sys/unix"
+)
+
+func TestInputEcho(t *testing.T) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Fatalf("pty.Open() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = master.Close()
+		_ = slave.Close()
+	})
+	go func() { _, _ = io.Copy(io.Discard, master) }()
+
+	echo := func() bool {
+		t.Helper()
+		termios, err := unix.IoctlGetTermios(int(slave.Fd()), ioctlReadTermios)
+		if err != nil {
+			t.Fatalf("error getting terminal state: %v", err)
+		}
+		return termios.Lflag&unix.ECHO != 0
+	}
+	waitForEcho := func(want bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for echo() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected echo to be %t", want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// Start with echo disabled to tell the original state apart from the
+	// terminal's default.
+	if err := setEcho(slave.Fd(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewProgram(&testModel{}, WithInput(slave), WithOutput(slave), WithoutSignalHandler())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	p.Send(EnableEcho())
+	waitForEcho(true)
+	p.Send(DisableEcho())
+	waitForEcho(false)
+	p.Send(EnableEcho())
+	waitForEcho(true)
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if echo() {
+		t.Fatal("expected echo to be restored on exit")
+	}
+}
+
+type lazyRawModeTestModel struct {
+	init Cmd
+	keys atomic.Int32
+}
+
+func (m *lazyRawModeTestModel) Init() Cmd { return m.init }
+
+func (m *lazyRawModeTestModel) Update(msg Msg) (Model, Cmd) {
+	if _, ok := msg.(KeyMsg); ok {
+		m.keys.Add(1)
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *lazyRawModeTestModel) View() string { return "lazy" }
+
+func TestLazyRawMode(t *testing.T) {
+	open := func(t *testing.T) (master, slave *os.File, raw func() bool) {
+		t.Helper()
+		master, slave, err := pty.Open()
+		if err != nil {
+			t.Fatalf("pty.Open() failed: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = master.Close()
+			_ = slave.Close()
+		})
+		go func() { _, _ = io.Copy(io.Discard, master) }()
+
+		return master, slave, func() bool {
+			termios, err := unix.IoctlGetTermios(int(slave.Fd()), ioctlReadTermios)
+			return err == nil && termios.Lflag&unix.ICANON == 0
+		}
+	}
+
+	t.Run("quit from init", func(t *testing.T) {
+		_, slave, raw := open(t)
+
+		var rawDuringInit atomic.Bool
+		m := &lazyRawModeTestModel{init: func() Msg {
+			time.Sleep(50 * time.Millisecond)
+			rawDuringInit.Store(raw())
+			return Quit()
+		}}
+		p := NewProgram(m, WithInput(slave), WithOutput(slave), WithLazyRawMode(), WithoutSignalHandler())
+		if _, err := p.Run(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rawDuringInit.Load() {
+			t.Fatal("expected the terminal not to be in raw mode while Init's command ran")
+		}
+		if raw() {
+			t.Fatal("expected the terminal not to be left in raw mode")
+		}
+	})
+
+	t.Run("enters once init ran", func(t *testing.T) {
+		master, slave, raw := open(t)
+
+		var rawDuringInit atomic.Bool
+		m := &lazyRawModeTestModel{init: func() Msg {
+			time.Sleep(50 * time.Millisecond)
+			rawDuringInit.Store(raw())
+			return nil
+		}}
+		p := NewProgram(m, WithInput(slave), WithOutput(slave), WithLazyRawMode(), WithoutSignalHandler())
+		errc := make(chan error, 1)
+		go func() {
+			_, err := p.Run()
+			errc <- err
+		}()
+
+		deadline := time.Now().Add(2 * time.Second)
+		for !raw() {
+			if time.Now().After(deadline) {
+				t.Fatal("expected the terminal to enter raw mode")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		if rawDuringInit.Load() {
+			t.Fatal("expected the terminal not to be in raw mode while Init's command ran")
+		}
+
+		// A single key press is read without a newline, so raw mode is on.
+		if _, err := master.Write([]byte("q")); err != nil {
+			t.Fatal(err)
+		}
+		if err := <-errc; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m.keys.Load() != 1 {
+			t.Fatalf("expected one key press, got %d", m.keys.Load())
+		}
+		if raw() {
+			t.Fatal("expected raw mode to be restored on exit")
+		}
+	})
+}
+
+func TestProgramFds(t *testing.T) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Fatalf("pty.Open() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = master.Close()
+		_ = slave.Close()
+	})
+
+	p := NewProgram(&testModel{}, WithInput(slave), WithOutput(slave))
+	if fd, ok := p.InputFd(); !ok || fd != slave.Fd() {
+		t.Fatalf("InputFd() = (%d, %t), want (%d, true)", fd, ok, slave.Fd())
+	}
+	if fd, ok := p.OutputFd(); !ok || fd != slave.Fd() {
+		t.Fatalf("OutputFd() = (%d, %t), want (%d, true)", fd, ok, slave.Fd())
+	}
+
+	var buf bytes.Buffer
+	p = NewProgram(&testModel{}, WithInput(&buf), WithOutput(&buf))
+	if _, ok := p.InputFd(); ok {
+		t.Fatal("expected no input fd for a buffer")
+	}
+	if _, ok := p.OutputFd(); ok {
+		t.Fatal("expected no output fd for a buffer")
+	}
+}
+
+func TestProgramInteractiveInput(t *testing.T) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Fatalf("pty.Open() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = master.Close()
+		_ = slave.Close()
+	})
+
+	if p := NewProgram(&testModel{}, WithInput(slave)); !p.InteractiveInput() {
+		t.Fatal("expected a TTY to be interactive input")
+	}
+	if p := NewProgram(&testModel{}, WithInput(&bytes.Buffer{})); p.InteractiveInput() {
+		t.Fatal("expected piped input not to be interactive")
+	}
+	if p := NewProgram(&testModel{}, WithInput(nil)); p.InteractiveInput() {
+		t.Fatal("expected no input not to be interactive")
+	}
+}
+
+func TestProgramRunInCookedMode(t *testing.T) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Fatalf("pty.Open() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = master.Close()
+		_ = slave.Close()
+	})
+	go func() { _, _ = io.Copy(io.Discard, master) }()
+
+	raw := func() bool {
+		t.Helper()
+		termios, err := unix.IoctlGetTermios(int(slave.Fd()), ioctlReadTermios)
+		if err != nil {
+			t.Fatalf("error getting terminal state: %v", err)
+		}
+		return termios.Lflag&unix.ICANON == 0
+	}
+
+	m := &testModel{}
+	p := NewProgram(m, WithInput(slave), WithOutput(slave), WithoutSignalHandler())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+	waitForModelExecution(t, m)
+
+	var cooked bool
+	if err := p.RunInCookedMode(func() error {
+		cooked = !raw()
+		return nil
+	}); err != nil {
+		t.Fatalf("RunInCookedMode() returned %v", err)
+	}
+	if !cooked {
+		t.Fatal("expected raw mode to be disabled while fn runs")
+	}
+	if !raw() {
+		t.Fatal("expected raw mode to be restored after fn returns")
+	}
+
+	func() {
+		defer func() { _ = recover() }()
+		_ = p.RunInCookedMode(func() error { panic("boom") })
+	}()
+	if !raw() {
+		t.Fatal("expected raw mode to be restored after fn panics")
+	}
+
+	p.Quit()
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSession(t *testing.T) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Fatalf("pty.Open() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = master.Close()
+		_ = slave.Close()
+	})
+	go func() { _, _ = io.Copy(io.Discard, master) }()
+
+	raw := func() bool {
+		t.Helper()
+		termios, err := unix.IoctlGetTermios(int(slave.Fd()), ioctlReadTermios)
+		if err != nil {
+			t.Fatalf("error getting terminal state: %v", err)
+		}
+		return termios.Lflag&unix.ICANON == 0
+	}
+
+	s := NewSession()
+	for range 2 {
+		m := &testModel{}
+		p := NewProgram(m, WithInput(slave), WithOutput(slave), WithAltScreen(), WithoutSignalHandler())
+		errc := make(chan error, 1)
+		go func() {
+			_, err := s.Run(p)
+			errc <- err
+		}()
+		waitForModelExecution(t, m)
+		p.Quit()
+		if err := <-errc; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !raw() {
+			t.Fatal("expected raw mode to be kept between programs")
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() returned %v", err)
+	}
+	if raw() {
+		t.Fatal("expected raw mode to be restored when closing the session")
+	}
+}
+
+func TestSessionWithOpenedTTY(t *testing.T) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Fatalf("pty.Open() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = master.Close()
+		_ = slave.Close()
+	})
+	go func() { _, _ = io.Copy(io.Discard, master) }()
+
+	// Programs open the terminal for input themselves, and close it when
+	// they're done with it.
+	var opened []*os.File
+	original := openInputTTY
+	openInputTTY = func() (*os.File, error) {
+		f, err := os.OpenFile(slave.Name(), os.O_RDWR, 0)
+		opened = append(opened, f)
+		return f, err
+	}
+	t.Cleanup(func() { openInputTTY = original })
+
+	raw := func() bool {
+		t.Helper()
+		termios, err := unix.IoctlGetTermios(int(slave.Fd()), ioctlReadTermios)
+		if err != nil {
+			t.Fatalf("error getting terminal state: %v", err)
+		}
+		return termios.Lflag&unix.ICANON == 0
+	}
+
+	s := NewSession()
+	for range 2 {
+		m := &testModel{}
+		p := NewProgram(m, WithInputTTY(), WithOutput(slave), WithAltScreen(), WithoutSignalHandler())
+		errc := make(chan error, 1)
+		go func() {
+			_, err := s.Run(p)
+			errc <- err
+		}()
+		waitForModelExecution(t, m)
+		p.Quit()
+		if err := <-errc; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !raw() {
+			t.Fatal("expected raw mode to be kept between programs")
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() returned %v", err)
+	}
+	if raw() {
+		t.Fatal("expected raw mode to be restored when closing the session")
+	}
+	for i, f := range opened {
+		if _, err := f.Stat(); !errors.Is(err, os.ErrClosed) {
+			t.Fatalf("expected the terminal opened by program %d to be closed, got %v", i+1, err)
+		}
+	}
+}
+
+type readLineTestModel struct {
+	line      string
+	canonical bool
+	fd        uintptr
+}
+
+type lineMsg string
+
+func (m *readLineTestModel) Init() Cmd {
+	return ReadLine("name: ", func(line string) Msg {
+		return lineMsg(line)
+	})
+}
+
+func (m *readLineTestModel) Update(msg Msg) (Model, Cmd) {
+	if msg, ok := msg.(lineMsg); ok {
+		m.line = string(msg)
+		termios, err := unix.IoctlGetTermios(int(m.fd), ioctlReadTermios)
+		m.canonical = err == nil && termios.Lflag&unix.ICANON != 0
+		if m.line == "" {
+			return m, nil
+		}
+		return m, Quit
+	}
+	return m, nil
+}
+
+func (m *readLineTestModel) View() string { return "view\n" }
+
+func TestReadLine(t *testing.T) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Fatalf("pty.Open() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = master.Close()
+		_ = slave.Close()
+	})
+
+	m := &readLineTestModel{fd: slave.Fd()}
+	p := NewProgram(m, WithInput(slave), WithOutput(slave))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	waitForOutput(t, master, "name: ")
+	if _, err := master.Write([]byte("gopher\n")); err != nil {
+		t.Fatal(err)
+	}
+	go func() { _, _ = io.Copy(io.Discard, master) }()
+
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.line != "gopher" {
+		t.Fatalf("expected the line %q, got %q", "gopher", m.line)
+	}
+	if m.canonical {
+		t.Fatal("expected raw mode to be restored after reading the line")
+	}
+}
+
+func TestReadLineInterrupted(t *testing.T) {
+	master, slave, err := pty.Open()
+	if err != nil {
+		t.Fatalf("pty.Open() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = master.Close()
+		_ = slave.Close()
+	})
+
+	m := &readLineTestModel{fd: slave.Fd(), line: "unset"}
+	p := NewProgram(m, WithInput(slave), WithOutput(slave))
+	errc := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		errc <- err
+	}()
+
+	waitForOutput(t, master, "name: ")
+	go func() { _, _ = io.Copy(io.Discard, master) }()
+	if err := unix.Kill(unix.Getpid(), unix.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-errc; !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("expected the program to be interrupted, got %v", err)
+	}
+	if m.line != "" {
+		t.Fatalf("expected an empty line, got %q", m.line)
+	}
+}
+
+// waitForOutput reads from r until s has been read.
+func waitForOutput(t *testing.T, r io.Reader, s string) {
+	t.Helper()
+	found := make(chan struct{})
+	go func() {
+		var out []byte
+		buf := make([]byte, 256)
+		for {
+			n, err := r.Read(buf)
+			out = append(out, buf[:n]...)
+			if bytes.Contains(out, []byte(s)) {
+				close(found)
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	select {
+	case <-found:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for %q", s)
+	}
+}
@@ -4,6 +4,7 @@
 package tea
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -17,19 +18,10 @@ func (p *Program) initInput() (err error) {
 	// input here.
 	if f, ok := p.input.(term.File); ok && term.IsTerminal(f.Fd()) {
 		p.ttyInput = f
-		p.previousTtyInputState, err = term.MakeRaw(p.ttyInput.Fd())
-		if err != nil {
-			return fmt.Errorf("error making raw: %w", err)
-		}
-
-		// Enable VT input
-		var mode uint32
-		if err := windows.GetConsoleMode(windows.Handle(p.ttyInput.Fd()), &mode); err != nil {
-			return fmt.Errorf("error getting console mode: %w", err)
-		}
-
-		if err := windows.SetConsoleMode(windows.Handle(p.ttyInput.Fd()), mode|windows.ENABLE_VIRTUAL_TERMINAL_INPUT); err != nil {
-			return fmt.Errorf("error setting console mode: %w", err)
+		if !p.startupOptions.has(withLazyRawMode) {
+			if err := p.enterRawMode(); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -54,8 +46,28 @@ func (p *Program) initInput() (err error) {
 	return nil
 }
 
+// makeInputRaw puts the tty input into raw mode and enables VT input,
+// returning its previous state.
+func (p *Program) makeInputRaw() (*term.State, error) {
+	state, err := term.MakeRaw(p.ttyInput.Fd())
+	if err != nil {
+		return nil, fmt.Errorf("error making raw: %w", err)
+	}
+
+	// Enable VT input
+	var mode uint32
+	if err := windows.GetConsoleMode(windows.Handle(p.ttyInput.Fd()), &mode); err != nil {
+		return state, fmt.Errorf("error getting console mode: %w", err)
+	}
+
+	if err := windows.SetConsoleMode(windows.Handle(p.ttyInput.Fd()), mode|windows.ENABLE_VIRTUAL_TERMINAL_INPUT); err != nil {
+		return state, fmt.Errorf("error setting console mode: %w", err)
+	}
+	return state, nil
+}
+
 // Open the Windows equivalent of a TTY.
-func openInputTTY() (*os.File, error) {
+var openInputTTY = func() (*os.File, error) {
 	f, err := os.OpenFile("CONIN$", os.O_RDWR, 0o644) //nolint:gosec
 	if err != nil {
 		return nil, fmt.Errorf("error opening file: %w", err)
@@ -66,3 +78,19 @@ func openInputTTY() (*os.File, error) {
 const suspendSupported = false
 
 var suspendProcess = func() {}
+
+// getWindowSize returns the size of the console in cells. Its size in pixels
+// isn't known.
+func getWindowSize(fd uintptr) (width, height, pixelWidth, pixelHeight int, err error) {
+	width, height, err = term.GetSize(fd)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("error getting window size: %w", err)
+	}
+	return width, height, 0, 0, nil
+}
+
+// setEcho isn't supported on Windows, where the console only echoes input in
+// line input mode.
+func setEcho(uintptr, bool) error {
+	return errors.New("input echo is not supported on Windows")
+}